@@ -0,0 +1,142 @@
+// Copyright 2026 xgfone
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package log
+
+import "context"
+
+type loggerCtxKey struct{}
+
+// NewContext returns a new context.Context carrying the logger.
+func NewContext(ctx context.Context, logger Logger) context.Context {
+	return context.WithValue(ctx, loggerCtxKey{}, logger)
+}
+
+// FromContext returns the logger carried by the context.
+//
+// If ctx is nil or does not carry the logger, return DefaultLogger.
+func FromContext(ctx context.Context) Logger {
+	if ctx != nil {
+		if logger, ok := ctx.Value(loggerCtxKey{}).(Logger); ok {
+			return logger
+		}
+	}
+	return DefaultLogger
+}
+
+/// ----------------------------------------------------------------------- ///
+
+// CtxExtractor is used to extract the key-value contexts from context.Context,
+// such as the request id, the tenant, the user, etc, which calls kv
+// for each extracted key-value.
+type CtxExtractor func(ctx context.Context, kv func(key string, value interface{}))
+
+var ctxExtractors []CtxExtractor
+
+// RegisterCtxExtractor registers the context extractors, which are run
+// in turn by Emitter.Ctx and Logger.WithCtx.
+//
+// Notice: it is not thread-safe and should be called during initializing.
+func RegisterCtxExtractor(extractors ...CtxExtractor) {
+	ctxExtractors = append(ctxExtractors, extractors...)
+}
+
+// CtxExtractors returns all the registered context extractors.
+func CtxExtractors() []CtxExtractor { return ctxExtractors }
+
+// CtxValueExtractor returns a context extractor, which extracts the value
+// by ctx.Value(ctxKey) and uses key as the log key if the value is not nil.
+func CtxValueExtractor(key string, ctxKey interface{}) CtxExtractor {
+	return func(ctx context.Context, kv func(string, interface{})) {
+		if value := ctx.Value(ctxKey); value != nil {
+			kv(key, value)
+		}
+	}
+}
+
+func extractCtx(ctx context.Context, kv func(string, interface{})) {
+	if ctx == nil {
+		return
+	}
+
+	for i, _len := 0, len(ctxExtractors); i < _len; i++ {
+		ctxExtractors[i](ctx, kv)
+	}
+}
+
+// Ctx runs the registered context extractors to append the key-value
+// contexts extracted from ctx into the log message, and returns
+// the emitter itself.
+func (e *Emitter) Ctx(ctx context.Context) *Emitter {
+	if e == nil {
+		return nil
+	}
+
+	extractCtx(ctx, func(key string, value interface{}) {
+		e.buffer = e.encoder.Encode(e.buffer, e.key(key), value)
+	})
+	return e
+}
+
+// WithCtx returns a new logger that appends the key-value contexts
+// extracted from ctx by the registered context extractors.
+func (l Logger) WithCtx(ctx context.Context) Logger {
+	l = l.Clone()
+	extractCtx(ctx, func(key string, value interface{}) {
+		l.appendContexts(key, value)
+	})
+	return l
+}
+
+/// ----------------------------------------------------------------------- ///
+
+// TraceCtx is equal to FromContext(ctx).Trace().Ctx(ctx).Kvs(kvs...).
+func TraceCtx(ctx context.Context, kvs ...interface{}) *Emitter {
+	return FromContext(ctx).getEmitter(LvlTrace, 1).Ctx(ctx).Kvs(kvs...)
+}
+
+// DebugCtx is equal to FromContext(ctx).Debug().Ctx(ctx).Kvs(kvs...).
+func DebugCtx(ctx context.Context, kvs ...interface{}) *Emitter {
+	return FromContext(ctx).getEmitter(LvlDebug, 1).Ctx(ctx).Kvs(kvs...)
+}
+
+// InfoCtx is equal to FromContext(ctx).Info().Ctx(ctx).Kvs(kvs...).
+func InfoCtx(ctx context.Context, kvs ...interface{}) *Emitter {
+	return FromContext(ctx).getEmitter(LvlInfo, 1).Ctx(ctx).Kvs(kvs...)
+}
+
+// WarnCtx is equal to FromContext(ctx).Warn().Ctx(ctx).Kvs(kvs...).
+func WarnCtx(ctx context.Context, kvs ...interface{}) *Emitter {
+	return FromContext(ctx).getEmitter(LvlWarn, 1).Ctx(ctx).Kvs(kvs...)
+}
+
+// ErrorCtx is equal to FromContext(ctx).Error().Ctx(ctx).Kvs(kvs...).
+func ErrorCtx(ctx context.Context, kvs ...interface{}) *Emitter {
+	return FromContext(ctx).getEmitter(LvlError, 1).Ctx(ctx).Kvs(kvs...)
+}
+
+// AlertCtx is equal to FromContext(ctx).Alert().Ctx(ctx).Kvs(kvs...).
+func AlertCtx(ctx context.Context, kvs ...interface{}) *Emitter {
+	return FromContext(ctx).getEmitter(LvlAlert, 1).Ctx(ctx).Kvs(kvs...)
+}
+
+// PanicCtx is equal to FromContext(ctx).Panic().Ctx(ctx).Kvs(kvs...).
+func PanicCtx(ctx context.Context, kvs ...interface{}) *Emitter {
+	return FromContext(ctx).getEmitter(LvlPanic, 1).Ctx(ctx).Kvs(kvs...)
+}
+
+// FatalCtx is equal to FromContext(ctx).Fatal().Ctx(ctx).Kvs(kvs...).
+func FatalCtx(ctx context.Context, kvs ...interface{}) *Emitter {
+	return FromContext(ctx).getEmitter(LvlFatal, 1).Ctx(ctx).Kvs(kvs...)
+}
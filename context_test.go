@@ -0,0 +1,67 @@
+// Copyright 2026 xgfone
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package log
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"strings"
+	"testing"
+)
+
+type testCtxKey string
+
+func TestContext(t *testing.T) {
+	extractors := ctxExtractors
+	defer func() { ctxExtractors = extractors }()
+	RegisterCtxExtractor(
+		CtxValueExtractor("rid", testCtxKey("rid")),
+		CtxValueExtractor("tenant", testCtxKey("tenant")),
+	)
+
+	defer func(w io.Writer, enc Encoder) {
+		DefaultLogger.SetWriter(w)
+		DefaultLogger.Output.SetEncoder(enc)
+	}(DefaultLogger.Output.GetWriter(), DefaultLogger.Output.GetEncoder())
+
+	buf := bytes.NewBuffer(nil)
+	DefaultLogger.SetWriter(buf)
+	DefaultLogger.Output.SetEncoder(newTestEncoder())
+	logger := New("ctx").WithWriter(buf).WithEncoder(newTestEncoder()).
+		WithHooks(Caller("caller"))
+
+	ctx := context.WithValue(context.Background(), testCtxKey("rid"), "123")
+	InfoCtx(ctx, "k1", "v1").Print("msg1")
+
+	ctx = NewContext(ctx, logger)
+	if FromContext(ctx).Name() != "ctx" {
+		t.Errorf("expect logger '%s', but got '%s'", "ctx", FromContext(ctx).Name())
+	}
+
+	ctx = context.WithValue(ctx, testCtxKey("tenant"), "abc")
+	ErrorCtx(ctx).Kv("k2", "v2").Print("msg2")
+	logger.WithCtx(ctx).Info().Print("msg3")
+	logger.Info().Ctx(context.Background()).Print("msg4")
+
+	expects := []string{
+		`{"lvl":"info","caller":"context_test.go:TestContext:47","rid":"123","k1":"v1","msg":"msg1"}`,
+		`{"lvl":"error","logger":"ctx","caller":"context_test.go:TestContext:55","rid":"123","tenant":"abc","k2":"v2","msg":"msg2"}`,
+		`{"lvl":"info","logger":"ctx","rid":"123","tenant":"abc","caller":"context_test.go:TestContext:56","msg":"msg3"}`,
+		`{"lvl":"info","logger":"ctx","caller":"context_test.go:TestContext:57","msg":"msg4"}`,
+		``,
+	}
+	testStrings(t, "context", expects, strings.Split(buf.String(), "\n"))
+}
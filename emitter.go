@@ -34,8 +34,18 @@ type Emitter struct {
 	encoder encoderProxy
 	buffer  []byte
 	level   int
+	pc      uintptr
+
+	// For the nested fields
+	prefix string
+	index  int
+	groups int
 }
 
+// PC returns the program counter of the caller given by Logger.LevelPC,
+// which should be used by the hooks instead of the stack depth if not 0.
+func (e *Emitter) PC() uintptr { return e.pc }
+
 // Enabled reports whether the log emitter is enabled.
 func (e *Emitter) Enabled() bool { return e != nil }
 
@@ -45,7 +55,7 @@ func (e *Emitter) Err(err error) *Emitter {
 		return nil
 	}
 
-	e.buffer = e.encoder.Encode(e.buffer, "err", err)
+	e.buffer = e.encoder.Encode(e.buffer, e.key("err"), err)
 	return e
 }
 
@@ -55,7 +65,7 @@ func (e *Emitter) Kv(key string, value interface{}) *Emitter {
 		return nil
 	}
 
-	e.buffer = e.encoder.Encode(e.buffer, key, value)
+	e.buffer = e.encoder.Encode(e.buffer, e.key(key), value)
 	return e
 }
 
@@ -72,7 +82,7 @@ func (e *Emitter) Kvs(kvs ...interface{}) *Emitter {
 	}
 
 	for i := 0; i < _len; i += 2 {
-		e.buffer = e.encoder.Encode(e.buffer, kvs[i].(string), kvs[i+1])
+		e.buffer = e.encoder.Encode(e.buffer, e.key(kvs[i].(string)), kvs[i+1])
 	}
 	return e
 }
@@ -101,6 +111,9 @@ func (e *Emitter) Printf(msg string, args ...interface{}) {
 
 func (e *Emitter) emit(msg string) {
 	level := e.level
+	for ; e.groups > 0; e.groups-- {
+		e.buffer = e.encoder.EncodeObjectEnd(e.buffer)
+	}
 	e.buffer = e.encoder.End(e.buffer, msg)
 	e.writer.WriteLevel(level, e.buffer)
 	e.buffer = e.buffer[:0]
@@ -116,7 +129,7 @@ func (e *Emitter) emit(msg string) {
 	}
 }
 
-func newEmitter(logger Logger, level int, depth int) *Emitter {
+func newEmitter(logger Logger, level int, depth int, pc uintptr) *Emitter {
 	if logger.isDisabled(level) {
 		return nil
 	}
@@ -125,18 +138,29 @@ func newEmitter(logger Logger, level int, depth int) *Emitter {
 	l.encoder = logger.Output.encoder
 	l.writer = logger.Output.writer
 	l.level = level
+	l.pc = pc
+	l.prefix = ""
+
+	// The fields added by the hooks are not in any group.
+	gctx := len(logger.ctx)
+	if len(logger.groups) > 0 {
+		gctx = logger.gctx
+	}
 
 	l.buffer = l.encoder.Start(l.buffer, logger.name, logger.FormatLevel(level))
-	l.buffer = append(l.buffer, logger.ctx...)
+	l.buffer = append(l.buffer, logger.ctx[:gctx]...)
 	for i, _len := 0, len(logger.hooks); i < _len; i++ {
 		logger.hooks[i].Run(l, logger.name, level, depth+2)
 	}
+	l.buffer = append(l.buffer, logger.ctx[gctx:]...)
 
+	l.prefix = logger.prefix
+	l.groups = logger.gclose
 	return l
 }
 
 func (l Logger) getEmitter(level, depth int) *Emitter {
-	return newEmitter(l, level, l.depth+depth)
+	return newEmitter(l, level, l.depth+depth, 0)
 }
 
 // Log is convenient function to emit a log, which is equal to
@@ -148,45 +172,55 @@ func (l Logger) Log(level, depth int, msg string, keysAndValues ...interface{})
 // Level returns an emitter with the level and the stack depth to emit the log.
 func (l Logger) Level(level, depth int) *Emitter {
 	checkLevel(level)
-	return newEmitter(l, level, l.depth+depth)
+	return newEmitter(l, level, l.depth+depth, 0)
+}
+
+// LevelPC is the same as Level, but the caller is identified by the program
+// counter pc instead of the stack depth, which is used by the adapter whose
+// caller has been resolved, such as slog.Record.PC. See Emitter.PC.
+//
+// If pc is 0, it is equal to l.Level(level, 0).
+func (l Logger) LevelPC(level int, pc uintptr) *Emitter {
+	checkLevel(level)
+	return newEmitter(l, level, l.depth, pc)
 }
 
 // Trace is equal to l.Level(LvlTrace, 0).Kvs(kvs...).
 func (l Logger) Trace(kvs ...interface{}) *Emitter {
-	return newEmitter(l, LvlTrace, l.depth).Kvs(kvs...)
+	return newEmitter(l, LvlTrace, l.depth, 0).Kvs(kvs...)
 }
 
 // Debug is equal to l.Level(LvlDebug, 0).Kvs(kvs...).
 func (l Logger) Debug(kvs ...interface{}) *Emitter {
-	return newEmitter(l, LvlDebug, l.depth).Kvs(kvs...)
+	return newEmitter(l, LvlDebug, l.depth, 0).Kvs(kvs...)
 }
 
 // Info is equal to l.Level(LvlInfo, 0).Kvs(kvs...).
 func (l Logger) Info(kvs ...interface{}) *Emitter {
-	return newEmitter(l, LvlInfo, l.depth).Kvs(kvs...)
+	return newEmitter(l, LvlInfo, l.depth, 0).Kvs(kvs...)
 }
 
 // Warn is equal to l.Level(LvlWarn, 0).Kvs(kvs...).
 func (l Logger) Warn(kvs ...interface{}) *Emitter {
-	return newEmitter(l, LvlWarn, l.depth).Kvs(kvs...)
+	return newEmitter(l, LvlWarn, l.depth, 0).Kvs(kvs...)
 }
 
 // Error is equal to l.Level(LvlError, 0).Kvs(kvs...).
 func (l Logger) Error(kvs ...interface{}) *Emitter {
-	return newEmitter(l, LvlError, l.depth).Kvs(kvs...)
+	return newEmitter(l, LvlError, l.depth, 0).Kvs(kvs...)
 }
 
 // Alert is equal to l.Level(LvlAlert, 0).Kvs(kvs...).
 func (l Logger) Alert(kvs ...interface{}) *Emitter {
-	return newEmitter(l, LvlAlert, l.depth).Kvs(kvs...)
+	return newEmitter(l, LvlAlert, l.depth, 0).Kvs(kvs...)
 }
 
 // Panic is equal to l.Level(LvlPanic, 0).Kvs(kvs...).
 func (l Logger) Panic(kvs ...interface{}) *Emitter {
-	return newEmitter(l, LvlPanic, l.depth).Kvs(kvs...)
+	return newEmitter(l, LvlPanic, l.depth, 0).Kvs(kvs...)
 }
 
 // Fatal is equal to l.Level(LvlFatal, 0).Kvs(kvs...).
 func (l Logger) Fatal(kvs ...interface{}) *Emitter {
-	return newEmitter(l, LvlFatal, l.depth).Kvs(kvs...)
+	return newEmitter(l, LvlFatal, l.depth, 0).Kvs(kvs...)
 }
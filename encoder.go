@@ -43,7 +43,13 @@ type encoderProxy struct {
 	encoder.TimeEncoder
 	encoder.DurationEncoder
 	encoder.StringSliceEncoder
+	encoder.ObjectEncoder
+	encoder.ArrayEncoder
 	Encoder
+
+	// nested reports whether the encoder supports the nested object and array.
+	// If not, the keys of the nested fields are flattened with the prefix.
+	nested bool
 }
 
 func newEncoder(orig Encoder) (enc encoderProxy) {
@@ -80,9 +86,30 @@ func newEncoder(orig Encoder) (enc encoderProxy) {
 	if enc.StringSliceEncoder, ok = orig.(encoder.StringSliceEncoder); !ok {
 		enc.StringSliceEncoder = strsEncoder{orig}
 	}
+	if objEncoder, ok := orig.(encoder.ObjectEncoder); ok {
+		if arrEncoder, ok := orig.(encoder.ArrayEncoder); ok {
+			enc.ObjectEncoder = objEncoder
+			enc.ArrayEncoder = arrEncoder
+			enc.nested = true
+		}
+	}
 	return
 }
 
+// Encode overrides the method Encode of the original encoder to encode
+// the value implementing encoder.ObjectMarshaler or encoder.ArrayMarshaler
+// by the original encoder, so that it works with any encoder.
+func (p *encoderProxy) Encode(dst []byte, key string, value interface{}) []byte {
+	switch v := value.(type) {
+	case encoder.ObjectMarshaler:
+		return encoder.EncodeObject(dst, p.Encoder, key, v)
+	case encoder.ArrayMarshaler:
+		return encoder.EncodeArray(dst, p.Encoder, key, v)
+	default:
+		return p.Encoder.Encode(dst, key, value)
+	}
+}
+
 /// ----------------------------------------------------------------------- ///
 
 type intEncoder struct{ Encoder }
@@ -97,7 +124,7 @@ func (e *Emitter) Int(key string, value int) *Emitter {
 		return nil
 	}
 
-	e.buffer = e.encoder.EncodeInt(e.buffer, key, value)
+	e.buffer = e.encoder.EncodeInt(e.buffer, e.key(key), value)
 	return e
 }
 
@@ -115,7 +142,7 @@ func (e *Emitter) Int64(key string, value int64) *Emitter {
 		return nil
 	}
 
-	e.buffer = e.encoder.EncodeInt64(e.buffer, key, value)
+	e.buffer = e.encoder.EncodeInt64(e.buffer, e.key(key), value)
 	return e
 }
 
@@ -133,7 +160,7 @@ func (e *Emitter) Uint(key string, value uint) *Emitter {
 		return nil
 	}
 
-	e.buffer = e.encoder.EncodeUint(e.buffer, key, value)
+	e.buffer = e.encoder.EncodeUint(e.buffer, e.key(key), value)
 	return e
 }
 
@@ -151,7 +178,7 @@ func (e *Emitter) Uint64(key string, value uint64) *Emitter {
 		return nil
 	}
 
-	e.buffer = e.encoder.EncodeUint64(e.buffer, key, value)
+	e.buffer = e.encoder.EncodeUint64(e.buffer, e.key(key), value)
 	return e
 }
 
@@ -169,7 +196,7 @@ func (e *Emitter) Float64(key string, value float64) *Emitter {
 		return nil
 	}
 
-	e.buffer = e.encoder.EncodeFloat64(e.buffer, key, value)
+	e.buffer = e.encoder.EncodeFloat64(e.buffer, e.key(key), value)
 	return e
 }
 
@@ -187,7 +214,7 @@ func (e *Emitter) Bool(key string, value bool) *Emitter {
 		return nil
 	}
 
-	e.buffer = e.encoder.EncodeBool(e.buffer, key, value)
+	e.buffer = e.encoder.EncodeBool(e.buffer, e.key(key), value)
 	return e
 }
 
@@ -205,7 +232,7 @@ func (e *Emitter) Str(key string, value string) *Emitter {
 		return nil
 	}
 
-	e.buffer = e.encoder.EncodeString(e.buffer, key, value)
+	e.buffer = e.encoder.EncodeString(e.buffer, e.key(key), value)
 	return e
 }
 
@@ -223,7 +250,7 @@ func (e *Emitter) Time(key string, value time.Time) *Emitter {
 		return nil
 	}
 
-	e.buffer = e.encoder.EncodeTime(e.buffer, key, value)
+	e.buffer = e.encoder.EncodeTime(e.buffer, e.key(key), value)
 	return e
 }
 
@@ -241,7 +268,7 @@ func (e *Emitter) Duration(key string, value time.Duration) *Emitter {
 		return nil
 	}
 
-	e.buffer = e.encoder.EncodeDuration(e.buffer, key, value)
+	e.buffer = e.encoder.EncodeDuration(e.buffer, e.key(key), value)
 	return e
 }
 
@@ -259,6 +286,6 @@ func (e *Emitter) StrSlice(key string, value []string) *Emitter {
 		return nil
 	}
 
-	e.buffer = e.encoder.EncodeStringSlice(e.buffer, key, value)
+	e.buffer = e.encoder.EncodeStringSlice(e.buffer, e.key(key), value)
 	return e
 }
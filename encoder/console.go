@@ -0,0 +1,340 @@
+// Copyright 2026 xgfone
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package encoder
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/xgfone/go-log/internal/term"
+)
+
+var (
+	_ IntEncoder         = &ConsoleEncoder{}
+	_ Int64Encoder       = &ConsoleEncoder{}
+	_ UintEncoder        = &ConsoleEncoder{}
+	_ Uint64Encoder      = &ConsoleEncoder{}
+	_ Float64Encoder     = &ConsoleEncoder{}
+	_ BoolEncoder        = &ConsoleEncoder{}
+	_ StringEncoder      = &ConsoleEncoder{}
+	_ TimeEncoder        = &ConsoleEncoder{}
+	_ DurationEncoder    = &ConsoleEncoder{}
+	_ StringSliceEncoder = &ConsoleEncoder{}
+)
+
+// The control characters used to mark the position of the message
+// and the multi-line blocks, which never occur in the encoded fields.
+const (
+	consoleMsgMark   = '\x1e'
+	consoleBlockMark = '\x1f'
+)
+
+const (
+	colorReset   = "\x1b[0m"
+	colorBold    = "\x1b[1m"
+	colorRed     = "\x1b[31m"
+	colorGreen   = "\x1b[32m"
+	colorYellow  = "\x1b[33m"
+	colorMagenta = "\x1b[35m"
+	colorCyan    = "\x1b[36m"
+	colorGray    = "\x1b[90m"
+	colorBoldRed = "\x1b[1;31m"
+)
+
+var consoleBufPool = sync.Pool{New: func() interface{} {
+	return &consoleBuffer{buf: make([]byte, 0, 256)}
+}}
+
+type consoleBuffer struct{ buf []byte }
+
+// ConsoleEncoder is a human-friendly log encoder for the development,
+// which encodes the log record as the format like
+//
+//	2026-01-02 03:04:05.000 INF   [a.b] message key1=value1 key2="value 2"
+//	    stacks:
+//	        file1.go:func1:12
+//	        file2.go:func2:34
+//
+// The key-values are encoded like LogfmtEncoder after the message.
+// But the string slice, such as the call stacks from WrapPanic, and the string
+// containing the newline are printed as the indented lines below the message.
+type ConsoleEncoder struct {
+	// If true, colorize the level and the message.
+	//
+	// Default: true only if the writer is a terminal and the environment
+	// variable NO_COLOR is not set.
+	Color bool
+
+	// TimeLayout is the layout to format the time when to emit the log record.
+	// If empty, the time is not output.
+	//
+	// Default: "2006-01-02 15:04:05.000"
+	TimeLayout string
+
+	logfmt LogfmtEncoder
+}
+
+// NewConsoleEncoder returns a new ConsoleEncoder, which will output the log
+// into the writer w, and enable the color only if w is a terminal detected
+// by ioctl and the environment variable NO_COLOR is not set.
+func NewConsoleEncoder(w io.Writer) *ConsoleEncoder {
+	return &ConsoleEncoder{
+		Color:      term.IsTerminal(w) && os.Getenv("NO_COLOR") == "",
+		TimeLayout: "2006-01-02 15:04:05.000",
+	}
+}
+
+// Start implements the interface Encoder.
+func (enc *ConsoleEncoder) Start(buf []byte, name, level string) []byte {
+	// Time
+	if len(enc.TimeLayout) > 0 {
+		buf = Now().AppendFormat(buf, enc.TimeLayout)
+		buf = append(buf, ' ')
+	}
+
+	// Level
+	color, short := consoleLevel(level)
+	if enc.Color {
+		buf = append(buf, color...)
+		buf = append(buf, short...)
+		buf = append(buf, colorReset...)
+	} else {
+		buf = append(buf, short...)
+	}
+	for i := len(short); i < 5; i++ {
+		buf = append(buf, ' ')
+	}
+	buf = append(buf, ' ')
+
+	// Logger
+	if len(name) > 0 {
+		buf = append(buf, '[')
+		buf = append(buf, name...)
+		buf = append(buf, ']', ' ')
+	}
+
+	return append(buf, consoleMsgMark)
+}
+
+// End implements the interface Encoder.
+func (enc *ConsoleEncoder) End(buf []byte, msg string) []byte {
+	mark := bytes.LastIndexByte(buf, consoleMsgMark)
+	if mark < 0 { // The record is not started by ConsoleEncoder.
+		mark = len(buf)
+		buf = append(buf, consoleMsgMark)
+	}
+
+	cbuf := consoleBufPool.Get().(*consoleBuffer)
+	fields := append(cbuf.buf[:0], buf[mark+1:]...)
+	buf = buf[:mark]
+
+	// Msg
+	switch {
+	case len(msg) == 0:
+		buf = bytes.TrimRight(buf, " ")
+	case enc.Color:
+		buf = append(buf, colorBold...)
+		buf = append(buf, msg...)
+		buf = append(buf, colorReset...)
+	default:
+		buf = append(buf, msg...)
+	}
+
+	// The inline key-values
+	var inBlock bool
+	for start, i, _len := 0, 0, len(fields); i <= _len; i++ {
+		if i < _len && fields[i] != consoleBlockMark {
+			continue
+		}
+
+		if !inBlock && start < i {
+			if segment := bytes.TrimRight(fields[start:i], " "); len(segment) > 0 {
+				buf = append(buf, ' ')
+				buf = append(buf, segment...)
+			}
+		}
+		inBlock = !inBlock
+		start = i + 1
+	}
+
+	// The multi-line blocks
+	for start, i, _len := 0, 0, len(fields); i < _len; i++ {
+		if fields[i] != consoleBlockMark {
+			continue
+		}
+
+		if start > 0 {
+			buf = append(buf, fields[start:i]...)
+			start = 0
+		} else {
+			start = i + 1
+		}
+	}
+
+	cbuf.buf = fields
+	consoleBufPool.Put(cbuf)
+
+	return append(buf, '\n')
+}
+
+// Encode implements the interface Encoder.
+func (enc *ConsoleEncoder) Encode(buf []byte, key string, value interface{}) []byte {
+	switch v := value.(type) {
+	case string:
+		return enc.EncodeString(buf, key, v)
+	case []string:
+		return enc.EncodeStringSlice(buf, key, v)
+	case ObjectMarshaler:
+		return EncodeObject(buf, enc, key, v)
+	case ArrayMarshaler:
+		return EncodeArray(buf, enc, key, v)
+	default:
+		return enc.logfmt.Encode(buf, key, value)
+	}
+}
+
+// EncodeInt implements the interface IntEncoder.
+func (enc *ConsoleEncoder) EncodeInt(dst []byte, key string, value int) []byte {
+	return enc.logfmt.EncodeInt(dst, key, value)
+}
+
+// EncodeInt64 implements the interface Int64Encoder.
+func (enc *ConsoleEncoder) EncodeInt64(dst []byte, key string, value int64) []byte {
+	return enc.logfmt.EncodeInt64(dst, key, value)
+}
+
+// EncodeUint implements the interface UintEncoder.
+func (enc *ConsoleEncoder) EncodeUint(dst []byte, key string, value uint) []byte {
+	return enc.logfmt.EncodeUint(dst, key, value)
+}
+
+// EncodeUint64 implements the interface Uint64Encoder.
+func (enc *ConsoleEncoder) EncodeUint64(dst []byte, key string, value uint64) []byte {
+	return enc.logfmt.EncodeUint64(dst, key, value)
+}
+
+// EncodeFloat64 implements the interface Float64Encoder.
+func (enc *ConsoleEncoder) EncodeFloat64(dst []byte, key string, value float64) []byte {
+	return enc.logfmt.EncodeFloat64(dst, key, value)
+}
+
+// EncodeBool implements the interface BoolEncoder.
+func (enc *ConsoleEncoder) EncodeBool(dst []byte, key string, value bool) []byte {
+	return enc.logfmt.EncodeBool(dst, key, value)
+}
+
+// EncodeString implements the interface StringEncoder.
+//
+// If value contains the newline, it is encoded as the multi-line block.
+func (enc *ConsoleEncoder) EncodeString(dst []byte, key string, value string) []byte {
+	if strings.IndexByte(value, '\n') < 0 {
+		return enc.logfmt.EncodeString(dst, key, value)
+	}
+
+	dst = appendConsoleBlockKey(dst, key)
+	for {
+		index := strings.IndexByte(value, '\n')
+		if index < 0 {
+			dst = appendConsoleBlockLine(dst, value)
+			break
+		}
+
+		dst = appendConsoleBlockLine(dst, value[:index])
+		value = value[index+1:]
+	}
+	return append(dst, consoleBlockMark)
+}
+
+// EncodeTime implements the interface TimeEncoder.
+func (enc *ConsoleEncoder) EncodeTime(dst []byte, key string, value time.Time) []byte {
+	return enc.logfmt.EncodeTime(dst, key, value)
+}
+
+// EncodeDuration implements the interface DurationEncoder.
+func (enc *ConsoleEncoder) EncodeDuration(dst []byte, key string, value time.Duration) []byte {
+	return enc.logfmt.EncodeDuration(dst, key, value)
+}
+
+// EncodeStringSlice implements the interface StringSliceEncoder,
+// which encodes value as the multi-line block, each line for an element.
+func (enc *ConsoleEncoder) EncodeStringSlice(dst []byte, key string, value []string) []byte {
+	if len(value) == 0 {
+		return enc.logfmt.EncodeStringSlice(dst, key, value)
+	}
+
+	dst = appendConsoleBlockKey(dst, key)
+	for _, line := range value {
+		dst = appendConsoleBlockLine(dst, line)
+	}
+	return append(dst, consoleBlockMark)
+}
+
+func appendConsoleBlockKey(dst []byte, key string) []byte {
+	dst = append(dst, consoleBlockMark, '\n', ' ', ' ', ' ', ' ')
+	dst = appendLogfmtKey(dst, key)
+	dst[len(dst)-1] = ':' // Replace '=' with ':'
+	return dst
+}
+
+func appendConsoleBlockLine(dst []byte, line string) []byte {
+	dst = append(dst, '\n', ' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ')
+	for i, _len := 0, len(line); i < _len; i++ {
+		switch c := line[i]; {
+		case c == '\t' || c >= ' ' && c != 0x7f:
+			dst = append(dst, c)
+		case c == '\r' && i == _len-1:
+		default:
+			dst = append(dst, '\\', 'x', hex[c>>4], hex[c&0xf])
+		}
+	}
+	return dst
+}
+
+const hex = "0123456789abcdef"
+
+// consoleLevel returns the color and the short name of the level,
+// such as "INF" for "info", "DBG5" for "debug5".
+func consoleLevel(level string) (color, short string) {
+	var prefix string
+	switch {
+	case strings.HasPrefix(level, "trace"):
+		prefix, short, color = "trace", "TRC", colorGray
+	case strings.HasPrefix(level, "debug"):
+		prefix, short, color = "debug", "DBG", colorMagenta
+	case strings.HasPrefix(level, "info"):
+		prefix, short, color = "info", "INF", colorGreen
+	case strings.HasPrefix(level, "warn"):
+		prefix, short, color = "warn", "WRN", colorYellow
+	case strings.HasPrefix(level, "error"):
+		prefix, short, color = "error", "ERR", colorRed
+	case strings.HasPrefix(level, "alert"):
+		prefix, short, color = "alert", "ALT", colorBoldRed
+	case strings.HasPrefix(level, "panic"):
+		prefix, short, color = "panic", "PNC", colorBoldRed
+	case strings.HasPrefix(level, "fatal"):
+		prefix, short, color = "fatal", "FTL", colorBoldRed
+	default:
+		return colorCyan, strings.ToUpper(level)
+	}
+
+	if len(level) > len(prefix) {
+		short += level[len(prefix):]
+	}
+	return
+}
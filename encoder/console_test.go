@@ -0,0 +1,68 @@
+// Copyright 2026 xgfone
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package encoder
+
+import (
+	"bytes"
+	"testing"
+	"time"
+)
+
+func TestConsoleEncoder(t *testing.T) {
+	now := time.Date(2026, time.January, 2, 3, 4, 5, 0, time.UTC)
+	defer func(f func() time.Time) { Now = f }(Now)
+	Now = func() time.Time { return now }
+
+	enc := NewConsoleEncoder(bytes.NewBuffer(nil))
+	if enc.Color {
+		t.Errorf("expect no color for the buffer")
+	}
+
+	buf := enc.Start(nil, "a.b", "info")
+	buf = enc.EncodeInt(buf, "k1", 1)
+	buf = enc.EncodeStringSlice(buf, "stacks", []string{"a.go:f:1", "b.go:g:2"})
+	buf = enc.EncodeString(buf, "k2", "a value")
+	buf = enc.Encode(buf, "text", "line1\r\nline2\x00")
+	buf = enc.Encode(buf, "user", testUser{Name: "xgfone"})
+	buf = enc.End(buf, "hello world")
+
+	expect := "2026-01-02 03:04:05.000 INF   [a.b] hello world k1=1 k2=\"a value\" user.name=xgfone user.age=0\n" +
+		"    stacks:\n" +
+		"        a.go:f:1\n" +
+		"        b.go:g:2\n" +
+		"    text:\n" +
+		"        line1\n" +
+		"        line2\\x00\n"
+	if s := string(buf); s != expect {
+		t.Errorf("expect '%s', but got '%s'", expect, s)
+	}
+
+	enc.Color = true
+	enc.TimeLayout = ""
+	buf = enc.Start(buf[:0], "", "debug5")
+	buf = enc.End(buf, "msg")
+	expect = "\x1b[35mDBG5\x1b[0m  \x1b[1mmsg\x1b[0m\n"
+	if s := string(buf); s != expect {
+		t.Errorf("expect '%q', but got '%q'", expect, s)
+	}
+
+	enc.Color = false
+	buf = enc.Start(buf[:0], "", "I")
+	buf = enc.EncodeBool(buf, "k", true)
+	buf = enc.End(buf, "")
+	if expect = "I k=true\n"; string(buf) != expect {
+		t.Errorf("expect '%q', but got '%q'", expect, string(buf))
+	}
+}
@@ -66,3 +66,35 @@ type DurationEncoder interface {
 type StringSliceEncoder interface {
 	EncodeStringSlice(dst []byte, key string, value []string) []byte
 }
+
+// ObjectEncoder is used to encode the nested object.
+type ObjectEncoder interface {
+	// EncodeObjectBegin begins to encode the nested object with the key.
+	EncodeObjectBegin(dst []byte, key string) []byte
+
+	// EncodeObjectEnd ends to encode the nested object,
+	// which is also used to end the object as the array element.
+	EncodeObjectEnd(dst []byte) []byte
+}
+
+// ArrayEncoder is used to encode the nested array.
+type ArrayEncoder interface {
+	// EncodeArrayBegin begins to encode the nested array with the key.
+	EncodeArrayBegin(dst []byte, key string) []byte
+
+	// EncodeArrayEnd ends to encode the nested array,
+	// which is also used to end the array as the array element.
+	EncodeArrayEnd(dst []byte) []byte
+
+	// EncodeArrayElem encodes the value as the element of the array,
+	// which should encode ObjectMarshaler and ArrayMarshaler by EncodeElem.
+	EncodeArrayElem(dst []byte, value interface{}) []byte
+
+	// EncodeElemObjectBegin begins to encode the nested object
+	// as the element of the array.
+	EncodeElemObjectBegin(dst []byte) []byte
+
+	// EncodeElemArrayBegin begins to encode the nested array
+	// as the element of the array.
+	EncodeElemArrayBegin(dst []byte) []byte
+}
@@ -35,6 +35,8 @@ var (
 	_ TimeEncoder        = &JSONEncoder{}
 	_ DurationEncoder    = &JSONEncoder{}
 	_ StringSliceEncoder = &JSONEncoder{}
+	_ ObjectEncoder      = &JSONEncoder{}
+	_ ArrayEncoder       = &JSONEncoder{}
 )
 
 // JSONEncoder is a log encoder to encode the log record as JSON.
@@ -112,9 +114,18 @@ func (enc *JSONEncoder) Start(buf []byte, name, level string) []byte {
 	return buf
 }
 
-// Encode implements the interface Encoder by using kvjson.JSON.EncodeKV.
+// Encode implements the interface Encoder by using kvjson.JSON.EncodeKV,
+// but the value implementing ObjectMarshaler or ArrayMarshaler is encoded
+// as the nested object or array.
 func (enc *JSONEncoder) Encode(buf []byte, key string, value interface{}) []byte {
-	return enc.JSON.EncodeKV(buf, key, value)
+	switch v := value.(type) {
+	case ObjectMarshaler:
+		return EncodeObject(buf, enc, key, v)
+	case ArrayMarshaler:
+		return EncodeArray(buf, enc, key, v)
+	default:
+		return enc.JSON.EncodeKV(buf, key, value)
+	}
 }
 
 // End implements the interface Encoder.
@@ -189,7 +200,7 @@ func (enc *JSONEncoder) EncodeUint64(dst []byte, key string, value uint64) []byt
 func (enc *JSONEncoder) EncodeFloat64(dst []byte, key string, value float64) []byte {
 	dst = kvjson.AppendJSONString(dst, key)
 	dst = append(dst, ':')
-	dst = strconv.AppendFloat(dst, value, 'f', -1, 64)
+	dst = enc.JSON.EncodeFloat64(dst, value)
 	dst = append(dst, ',')
 	return dst
 }
@@ -251,3 +262,66 @@ func (enc *JSONEncoder) EncodeStringSlice(dst []byte, key string, value []string
 	dst = append(dst, ',')
 	return dst
 }
+
+/// ----------------------------------------------------------------------- ///
+
+// EncodeObjectBegin implements the interface ObjectEncoder.
+func (enc *JSONEncoder) EncodeObjectBegin(dst []byte, key string) []byte {
+	dst = kvjson.AppendJSONString(dst, key)
+	dst = append(dst, ':')
+	dst = append(dst, '{')
+	return dst
+}
+
+// EncodeObjectEnd implements the interface ObjectEncoder.
+func (enc *JSONEncoder) EncodeObjectEnd(dst []byte) []byte {
+	dst = closeJSON(dst, '}')
+	dst = append(dst, ',')
+	return dst
+}
+
+// EncodeArrayBegin implements the interface ArrayEncoder.
+func (enc *JSONEncoder) EncodeArrayBegin(dst []byte, key string) []byte {
+	dst = kvjson.AppendJSONString(dst, key)
+	dst = append(dst, ':')
+	dst = append(dst, '[')
+	return dst
+}
+
+// EncodeArrayEnd implements the interface ArrayEncoder.
+func (enc *JSONEncoder) EncodeArrayEnd(dst []byte) []byte {
+	dst = closeJSON(dst, ']')
+	dst = append(dst, ',')
+	return dst
+}
+
+// EncodeArrayElem implements the interface ArrayEncoder.
+func (enc *JSONEncoder) EncodeArrayElem(dst []byte, value interface{}) []byte {
+	if dst, ok := EncodeElem(dst, enc, value); ok {
+		return dst
+	}
+
+	dst = enc.JSON.EncodeAny(dst, value)
+	dst = append(dst, ',')
+	return dst
+}
+
+// EncodeElemObjectBegin implements the interface ArrayEncoder.
+func (enc *JSONEncoder) EncodeElemObjectBegin(dst []byte) []byte {
+	return append(dst, '{')
+}
+
+// EncodeElemArrayBegin implements the interface ArrayEncoder.
+func (enc *JSONEncoder) EncodeElemArrayBegin(dst []byte) []byte {
+	return append(dst, '[')
+}
+
+// closeJSON replaces the trailing comma of the last member with the end
+// character c, or appends c if the object or array is empty.
+func closeJSON(dst []byte, c byte) []byte {
+	if _len := len(dst) - 1; _len >= 0 && dst[_len] == ',' {
+		dst[_len] = c
+		return dst
+	}
+	return append(dst, c)
+}
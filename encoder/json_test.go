@@ -17,9 +17,12 @@ package encoder
 import (
 	"encoding/json"
 	"errors"
+	"math"
 	"reflect"
 	"testing"
 	"time"
+
+	"github.com/xgfone/go-log/encoder/kvjson"
 )
 
 func TestJSONEncoder(t *testing.T) {
@@ -64,8 +67,8 @@ func TestJSONEncoder(t *testing.T) {
 	buf = enc.Encode(buf, "[]int64", []int64{36, 37})
 	buf = enc.Encode(buf, "map[string]interface{}", map[string]interface{}{"a": "38", "b": "39"})
 	buf = enc.Encode(buf, "map[string]string", map[string]string{"c": "40", "d": "41"})
-	buf = enc.Encode(buf, "bytes1", []byte(`"42"`))
-	buf = enc.Encode(buf, "bytes2", []byte(`[43, 44]`))
+	buf = enc.Encode(buf, "bytes1", kvjson.RawJSON(`"42"`))
+	buf = enc.Encode(buf, "bytes2", kvjson.RawJSON(`[43, 44]`))
 	buf = enc.End(buf, `"test json encoder"`)
 
 	type encoderT struct {
@@ -167,3 +170,91 @@ func TestJSONEncoder(t *testing.T) {
 		t.Errorf("expect '%+v', but got '%+v'", expect, result)
 	}
 }
+
+func TestJSONEncoderNested(t *testing.T) {
+	enc := NewJSONEncoder()
+	enc.TimeKey = ""
+
+	buf := enc.Start(nil, "", "info")
+	buf = enc.EncodeObjectBegin(buf, "k1")
+	buf = enc.EncodeString(buf, "k11", "v11")
+	buf = enc.EncodeArrayBegin(buf, "k12")
+	buf = enc.EncodeArrayElem(buf, 121)
+	buf = enc.EncodeElemObjectBegin(buf)
+	buf = enc.EncodeObjectEnd(buf)
+	buf = enc.EncodeElemArrayBegin(buf)
+	buf = enc.EncodeArrayElem(buf, "v123")
+	buf = enc.EncodeArrayEnd(buf)
+	buf = enc.EncodeArrayEnd(buf)
+	buf = enc.EncodeObjectEnd(buf)
+	buf = enc.End(buf, "msg")
+
+	expect := `{"lvl":"info","k1":{"k11":"v11","k12":[121,{},["v123"]]},"msg":"msg"}` + "\n"
+	if s := string(buf); s != expect {
+		t.Errorf("expect '%s', but got '%s'", expect, s)
+	}
+}
+
+func TestJSONEncoderValid(t *testing.T) {
+	values := []interface{}{
+		[]byte("\xff\x00binary\n"),
+		[]byte(nil),
+		kvjson.RawJSON(nil),
+		kvjson.RawJSON(`{"a":1}`),
+		math.NaN(),
+		math.Inf(1),
+		float32(math.Inf(-1)),
+		[]interface{}{math.NaN(), []byte("a")},
+		map[string]interface{}{"inf": math.Inf(1)},
+	}
+
+	modes := []struct {
+		Bytes     kvjson.BytesMode
+		NonFinite kvjson.NonFiniteMode
+	}{
+		{kvjson.BytesString, kvjson.NonFiniteString},
+		{kvjson.BytesBase64, kvjson.NonFiniteNull},
+		{kvjson.BytesHex, kvjson.NonFiniteError},
+	}
+
+	for _, mode := range modes {
+		enc := NewJSONEncoder()
+		enc.BytesMode = mode.Bytes
+		enc.NonFiniteMode = mode.NonFinite
+
+		var lines [][]byte
+		for _, value := range values {
+			buf := enc.Start(nil, "", "info")
+			buf = enc.Encode(buf, "value", value)
+			buf = enc.EncodeFloat64(buf, "float", math.Inf(-1))
+			lines = append(lines, enc.End(buf, "msg"))
+		}
+
+		for _, line := range lines {
+			var v interface{}
+			if err := json.Unmarshal(line, &v); err != nil {
+				t.Errorf("invalid json line '%s': %s", line, err)
+			}
+		}
+	}
+
+	var j kvjson.JSON
+	expects := map[string]string{
+		`"\ufffdab"`: string(j.EncodeAny(nil, []byte("\xffab"))),
+		`"NaN"`:      string(j.EncodeAny(nil, math.NaN())),
+	}
+
+	j.BytesMode, j.NonFiniteMode = kvjson.BytesBase64, kvjson.NonFiniteNull
+	expects[`"/2Fi"`] = string(j.EncodeAny(nil, []byte("\xffab")))
+	expects[`null`] = string(j.EncodeFloat64(nil, math.Inf(1)))
+
+	j.BytesMode, j.NonFiniteMode = kvjson.BytesHex, kvjson.NonFiniteError
+	expects[`"ff6162"`] = string(j.EncodeAny(nil, []byte("\xffab")))
+	expects[`"JSONError: unsupported value: -Inf"`] = string(j.EncodeFloat64(nil, math.Inf(-1)))
+
+	for expect, result := range expects {
+		if expect != result {
+			t.Errorf("expect '%s', but got '%s'", expect, result)
+		}
+	}
+}
@@ -16,9 +16,13 @@
 package kvjson
 
 import (
+	"encoding/base64"
 	"encoding/json"
 	"fmt"
 	"io"
+	"math"
+	"reflect"
+	"sort"
 	"strconv"
 	"time"
 )
@@ -30,12 +34,64 @@ var FormatTime = func(buf []byte, t time.Time) []byte {
 	return append(buf, '"')
 }
 
+// BytesMode is the mode to encode the value typed []byte.
+type BytesMode int
+
+const (
+	// BytesString encodes []byte as the JSON string, and the invalid UTF-8
+	// characters are replaced with the replacement character U+FFFD.
+	BytesString BytesMode = iota
+
+	// BytesBase64 encodes []byte as the JSON string by the standard base64.
+	BytesBase64
+
+	// BytesHex encodes []byte as the JSON string by the lower hex.
+	BytesHex
+)
+
+// NonFiniteMode is the mode to encode the non-finite float, that's, NaN,
+// +Inf and -Inf, which are not supported by JSON.
+type NonFiniteMode int
+
+const (
+	// NonFiniteString encodes the non-finite float as the JSON string,
+	// such as "NaN", "+Inf" and "-Inf".
+	NonFiniteString NonFiniteMode = iota
+
+	// NonFiniteNull encodes the non-finite float as null.
+	NonFiniteNull
+
+	// NonFiniteError encodes the non-finite float as the error string,
+	// such as "JSONError: unsupported value: NaN".
+	NonFiniteError
+)
+
+// RawJSON is the raw encoded JSON value, which is appended verbatim.
+// So it must be the valid JSON, and nil is encoded as null.
+type RawJSON []byte
+
 // JSON is used to encode the key-value json.
 type JSON struct {
 	// TimeFormatFunc is used to format time.Time.
 	//
 	// Default: FormatTime
 	TimeFormatFunc func(dst []byte, t time.Time) []byte
+
+	// If true, encode the keys of the map in the sorted order,
+	// which makes the output deterministic.
+	//
+	// Default: false
+	SortKeys bool
+
+	// BytesMode is the mode to encode the value typed []byte.
+	//
+	// Default: BytesString
+	BytesMode BytesMode
+
+	// NonFiniteMode is the mode to encode NaN, +Inf and -Inf.
+	//
+	// Default: NonFiniteString
+	NonFiniteMode NonFiniteMode
 }
 
 // EncodeStart writes the json start character "{".
@@ -49,7 +105,8 @@ func (j JSON) EncodeEnd(buf []byte) []byte {
 }
 
 // EncodeKV encodes the key-value pair, which supports not only the basic
-// or builtin types, but also other interfaces as follow:
+// or builtin types, RawJSON and the maps whose key is the string kind,
+// but also other interfaces as follow:
 //
 //   - error
 //   - fmt.Stringer
@@ -74,6 +131,12 @@ func (j JSON) EncodeTime(buf []byte, t time.Time) []byte {
 	return j.appendTime(buf, t)
 }
 
+// EncodeFloat64 encodes the float64 value, and the non-finite value
+// is encoded by NonFiniteMode.
+func (j JSON) EncodeFloat64(buf []byte, f float64) []byte {
+	return j.appendFloat(buf, f, 64)
+}
+
 // EncodeAny encodes the any value as JSON.
 func (j JSON) EncodeAny(buf []byte, v interface{}) []byte {
 	return j.appendAny(buf, v)
@@ -97,6 +160,50 @@ func (j JSON) appendTime(buf []byte, t time.Time) []byte {
 	return buf
 }
 
+func (j JSON) appendFloat(buf []byte, f float64, bits int) []byte {
+	var s string
+	switch {
+	case math.IsNaN(f):
+		s = "NaN"
+	case math.IsInf(f, 1):
+		s = "+Inf"
+	case math.IsInf(f, -1):
+		s = "-Inf"
+	default:
+		return strconv.AppendFloat(buf, f, 'f', -1, bits)
+	}
+
+	switch j.NonFiniteMode {
+	case NonFiniteNull:
+		return append(buf, `null`...)
+	case NonFiniteError:
+		return AppendJSONString(buf, "JSONError: unsupported value: "+s)
+	default:
+		return AppendJSONString(buf, s)
+	}
+}
+
+func (j JSON) appendBytes(buf []byte, b []byte) []byte {
+	switch j.BytesMode {
+	case BytesBase64:
+		buf = append(buf, '"')
+		n := len(buf)
+		buf = append(buf, make([]byte, base64.StdEncoding.EncodedLen(len(b)))...)
+		base64.StdEncoding.Encode(buf[n:], b)
+		return append(buf, '"')
+
+	case BytesHex:
+		buf = append(buf, '"')
+		for _, c := range b {
+			buf = append(buf, hex[c>>4], hex[c&0xf])
+		}
+		return append(buf, '"')
+
+	default:
+		return AppendJSONString(buf, string(b))
+	}
+}
+
 func (j JSON) appendAny(buf []byte, any interface{}) []byte {
 	switch v := any.(type) {
 	case time.Duration:
@@ -148,10 +255,10 @@ func (j JSON) appendAny(buf []byte, any interface{}) []byte {
 		buf = strconv.AppendUint(buf, v, 10)
 
 	case float32:
-		buf = strconv.AppendFloat(buf, float64(v), 'f', -1, 32)
+		buf = j.appendFloat(buf, float64(v), 32)
 
 	case float64:
-		buf = strconv.AppendFloat(buf, v, 'f', -1, 64)
+		buf = j.appendFloat(buf, v, 64)
 
 	case string:
 		buf = AppendJSONString(buf, v)
@@ -194,22 +301,22 @@ func (j JSON) appendAny(buf []byte, any interface{}) []byte {
 		}
 		buf = append(buf, ']')
 
-	case []byte:
+	case RawJSON:
 		if v == nil {
 			buf = append(buf, "null"...)
 		} else {
 			buf = append(buf, v...)
 		}
 
-	case []string:
-		buf = append(buf, '[')
-		for i, _v := range v {
-			if i > 0 {
-				buf = append(buf, ',')
-			}
-			buf = AppendJSONString(buf, _v)
+	case []byte:
+		if v == nil {
+			buf = append(buf, "null"...)
+		} else {
+			buf = j.appendBytes(buf, v)
 		}
-		buf = append(buf, ']')
+
+	case []string:
+		buf = appendStrings(buf, v)
 
 	case []uint:
 		buf = append(buf, '[')
@@ -252,37 +359,46 @@ func (j JSON) appendAny(buf []byte, any interface{}) []byte {
 		buf = append(buf, ']')
 
 	case map[string]interface{}:
-		buf = append(buf, '{')
-		var i int
-		for key, value := range v {
-			if i > 0 {
-				buf = append(buf, ',')
-			}
-			i++
-
-			buf = AppendJSONString(buf, key)
-			buf = append(buf, ':')
-			buf = j.appendAny(buf, value)
+		keys := make([]string, 0, len(v))
+		for key := range v {
+			keys = append(keys, key)
 		}
-		buf = append(buf, '}')
+		buf = j.appendMapKeys(buf, keys, func(buf []byte, key string) []byte {
+			return j.appendAny(buf, v[key])
+		})
 
 	case map[string]string:
-		buf = append(buf, '{')
-		var i int
-		for key, value := range v {
-			if i > 0 {
-				buf = append(buf, ',')
-			}
-			i++
-
-			buf = AppendJSONString(buf, key)
-			buf = append(buf, ':')
-			buf = AppendJSONString(buf, value)
+		keys := make([]string, 0, len(v))
+		for key := range v {
+			keys = append(keys, key)
+		}
+		buf = j.appendMapKeys(buf, keys, func(buf []byte, key string) []byte {
+			return AppendJSONString(buf, v[key])
+		})
+
+	case map[string]int:
+		keys := make([]string, 0, len(v))
+		for key := range v {
+			keys = append(keys, key)
 		}
-		buf = append(buf, '}')
+		buf = j.appendMapKeys(buf, keys, func(buf []byte, key string) []byte {
+			return strconv.AppendInt(buf, int64(v[key]), 10)
+		})
+
+	case map[string][]string:
+		keys := make([]string, 0, len(v))
+		for key := range v {
+			keys = append(keys, key)
+		}
+		buf = j.appendMapKeys(buf, keys, func(buf []byte, key string) []byte {
+			return appendStrings(buf, v[key])
+		})
 
 	default:
-		if data, err := json.Marshal(v); err != nil {
+		if rv := reflect.ValueOf(v); rv.Kind() == reflect.Map &&
+			rv.Type().Key().Kind() == reflect.String {
+			buf = j.appendMap(buf, rv)
+		} else if data, err := json.Marshal(v); err != nil {
 			buf = AppendJSONString(buf, fmt.Sprintf("JSONError: %s", err.Error()))
 		} else {
 			buf = append(buf, data...)
@@ -291,3 +407,64 @@ func (j JSON) appendAny(buf []byte, any interface{}) []byte {
 
 	return buf
 }
+
+// appendMapKeys appends the map with the keys, which are sorted if SortKeys
+// is true, and appendValue appends the value of each key.
+func (j JSON) appendMapKeys(buf []byte, keys []string,
+	appendValue func(buf []byte, key string) []byte) []byte {
+	if j.SortKeys {
+		sort.Strings(keys)
+	}
+
+	buf = append(buf, '{')
+	for i, key := range keys {
+		buf = appendMapKey(buf, i, key)
+		buf = appendValue(buf, key)
+	}
+	return append(buf, '}')
+}
+
+// appendMap appends the map whose key is the string kind by reflection.
+func (j JSON) appendMap(buf []byte, v reflect.Value) []byte {
+	if v.IsNil() {
+		return append(buf, `null`...)
+	}
+
+	keys := v.MapKeys()
+	if j.SortKeys {
+		sort.Sort(mapKeys(keys))
+	}
+
+	buf = append(buf, '{')
+	for i, key := range keys {
+		buf = appendMapKey(buf, i, key.String())
+		buf = j.appendAny(buf, v.MapIndex(key).Interface())
+	}
+	return append(buf, '}')
+}
+
+type mapKeys []reflect.Value
+
+func (ks mapKeys) Len() int           { return len(ks) }
+func (ks mapKeys) Less(i, j int) bool { return ks[i].String() < ks[j].String() }
+func (ks mapKeys) Swap(i, j int)      { ks[i], ks[j] = ks[j], ks[i] }
+
+// appendMapKey appends the i-th key of the map with the separators.
+func appendMapKey(buf []byte, i int, key string) []byte {
+	if i > 0 {
+		buf = append(buf, ',')
+	}
+	buf = AppendJSONString(buf, key)
+	return append(buf, ':')
+}
+
+func appendStrings(buf []byte, ss []string) []byte {
+	buf = append(buf, '[')
+	for i, s := range ss {
+		if i > 0 {
+			buf = append(buf, ',')
+		}
+		buf = AppendJSONString(buf, s)
+	}
+	return append(buf, ']')
+}
@@ -0,0 +1,62 @@
+// Copyright 2026 xgfone
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kvjson
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+type testKey string
+
+func TestJSONSortKeys(t *testing.T) {
+	values := []interface{}{
+		map[string]interface{}{"c": 1, "a": "2", "b": map[string]int{"y": 1, "x": 2}},
+		map[string]string{"c": "1", "a": "2", "b": "3"},
+		map[string]int{"c": 1, "a": 2, "b": 3},
+		map[string][]string{"c": {"1"}, "a": {"2", "3"}, "b": nil},
+		map[testKey]float64{"c": 1.5, "a": 2, "b": 3},
+		map[string]bool(nil),
+	}
+	expects := []string{
+		`{"a":"2","b":{"x":2,"y":1},"c":1}`,
+		`{"a":"2","b":"3","c":"1"}`,
+		`{"a":2,"b":3,"c":1}`,
+		`{"a":["2","3"],"b":[],"c":["1"]}`,
+		`{"a":2,"b":3,"c":1.5}`,
+		`null`,
+	}
+
+	sorted := JSON{SortKeys: true}
+	for i, value := range values {
+		// Encode multiple times to ensure the output is deterministic.
+		for j := 0; j < 10; j++ {
+			if s := string(sorted.EncodeAny(nil, value)); s != expects[i] {
+				t.Fatalf("%d: expect '%s', but got '%s'", i, expects[i], s)
+			}
+		}
+	}
+
+	var unsorted JSON
+	for i, value := range values {
+		var result, expect interface{}
+		if err := json.Unmarshal(unsorted.EncodeAny(nil, value), &result); err != nil {
+			t.Errorf("%d: %s", i, err)
+		} else if _ = json.Unmarshal([]byte(expects[i]), &expect); !reflect.DeepEqual(result, expect) {
+			t.Errorf("%d: expect '%v', but got '%v'", i, expect, result)
+		}
+	}
+}
@@ -0,0 +1,450 @@
+// Copyright 2026 xgfone
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package encoder
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"time"
+	"unicode/utf8"
+
+	"github.com/xgfone/go-log/encoder/kvjson"
+)
+
+var (
+	_ IntEncoder         = &LogfmtEncoder{}
+	_ Int64Encoder       = &LogfmtEncoder{}
+	_ UintEncoder        = &LogfmtEncoder{}
+	_ Uint64Encoder      = &LogfmtEncoder{}
+	_ Float64Encoder     = &LogfmtEncoder{}
+	_ BoolEncoder        = &LogfmtEncoder{}
+	_ StringEncoder      = &LogfmtEncoder{}
+	_ TimeEncoder        = &LogfmtEncoder{}
+	_ DurationEncoder    = &LogfmtEncoder{}
+	_ StringSliceEncoder = &LogfmtEncoder{}
+)
+
+// LogfmtEncoder is a log encoder to encode the log record as logfmt,
+// such as `t=2026-01-02T03:04:05Z lvl=info logger=a.b key="a value" msg=msg`.
+//
+// The value is quoted as the JSON string only if it is empty or contains
+// the space, '=', '"', the control or invalid UTF-8 characters. And the invalid
+// characters in the key are replaced with '_'.
+//
+// Because logfmt does not support the nested value, the map, slice and array,
+// and ObjectMarshaler and ArrayMarshaler are flattened, whose keys are
+// prefixed with key+".", such as "key.subkey", "key.0", "key.1", etc.
+// And the keys of the map are sorted to keep the output stable.
+type LogfmtEncoder struct {
+	// TimeFormatFunc is used to format time.Time, which should not append
+	// the space or other characters that need to be quoted.
+	//
+	// Default: append the time formatted by time.RFC3339Nano
+	TimeFormatFunc func(dst []byte, t time.Time) []byte
+
+	// If true, append a newline when emit the log record.
+	//
+	// Default: true
+	Newline bool
+
+	// TimeKey is the key name of the time when to emit the log record if not empty.
+	//
+	// Default: "t"
+	TimeKey string
+
+	// LevelKey is the key name of the level if not empty.
+	//
+	// Default: "lvl"
+	LevelKey string
+
+	// LoggerKey is the key name of the logger name.
+	//
+	// Default: "logger"
+	LoggerKey string
+
+	// MsgKey is the key name of the message if not empty.
+	//
+	// Default: "msg"
+	MsgKey string
+}
+
+// NewLogfmtEncoder returns a new LogfmtEncoder.
+func NewLogfmtEncoder() *LogfmtEncoder {
+	return &LogfmtEncoder{
+		Newline:   true,
+		TimeKey:   "t",
+		LevelKey:  "lvl",
+		LoggerKey: "logger",
+		MsgKey:    "msg",
+	}
+}
+
+// Start implements the interface Encoder.
+func (enc *LogfmtEncoder) Start(buf []byte, name, level string) []byte {
+	// Time
+	if len(enc.TimeKey) > 0 {
+		buf = appendLogfmtKey(buf, enc.TimeKey)
+		buf = enc.appendTime(buf, Now())
+		buf = append(buf, ' ')
+	}
+
+	// Level
+	if len(enc.LevelKey) > 0 {
+		buf = enc.EncodeString(buf, enc.LevelKey, level)
+	}
+
+	// Logger
+	if len(enc.LoggerKey) > 0 && len(name) > 0 {
+		buf = enc.EncodeString(buf, enc.LoggerKey, name)
+	}
+
+	return buf
+}
+
+// Encode implements the interface Encoder.
+func (enc *LogfmtEncoder) Encode(buf []byte, key string, value interface{}) []byte {
+	switch v := value.(type) {
+	case nil:
+		return enc.encodeRaw(buf, key, `null`)
+
+	case bool:
+		return enc.EncodeBool(buf, key, v)
+
+	case int:
+		return enc.EncodeInt64(buf, key, int64(v))
+
+	case int8:
+		return enc.EncodeInt64(buf, key, int64(v))
+
+	case int16:
+		return enc.EncodeInt64(buf, key, int64(v))
+
+	case int32:
+		return enc.EncodeInt64(buf, key, int64(v))
+
+	case int64:
+		return enc.EncodeInt64(buf, key, v)
+
+	case uint:
+		return enc.EncodeUint64(buf, key, uint64(v))
+
+	case uint8:
+		return enc.EncodeUint64(buf, key, uint64(v))
+
+	case uint16:
+		return enc.EncodeUint64(buf, key, uint64(v))
+
+	case uint32:
+		return enc.EncodeUint64(buf, key, uint64(v))
+
+	case uint64:
+		return enc.EncodeUint64(buf, key, v)
+
+	case float32:
+		buf = appendLogfmtKey(buf, key)
+		buf = strconv.AppendFloat(buf, float64(v), 'f', -1, 32)
+		return append(buf, ' ')
+
+	case float64:
+		return enc.EncodeFloat64(buf, key, v)
+
+	case string:
+		return enc.EncodeString(buf, key, v)
+
+	case time.Time:
+		return enc.EncodeTime(buf, key, v)
+
+	case time.Duration:
+		return enc.EncodeDuration(buf, key, v)
+
+	case ObjectMarshaler:
+		return EncodeObject(buf, enc, key, v)
+
+	case ArrayMarshaler:
+		return EncodeArray(buf, enc, key, v)
+
+	case error:
+		return enc.EncodeString(buf, key, v.Error())
+
+	case fmt.Stringer:
+		return enc.EncodeString(buf, key, v.String())
+
+	case json.RawMessage:
+		return enc.EncodeString(buf, key, string(v))
+
+	case kvjson.RawJSON:
+		return enc.EncodeString(buf, key, string(v))
+
+	case []byte:
+		return enc.EncodeString(buf, key, string(v))
+
+	case []string:
+		return enc.EncodeStringSlice(buf, key, v)
+
+	case []interface{}:
+		if len(v) == 0 {
+			return enc.encodeRaw(buf, key, `[]`)
+		}
+		for i, e := range v {
+			buf = enc.Encode(buf, key+"."+strconv.Itoa(i), e)
+		}
+		return buf
+
+	case map[string]interface{}:
+		if len(v) == 0 {
+			return enc.encodeRaw(buf, key, `{}`)
+		}
+		keys := make([]string, 0, len(v))
+		for k := range v {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			buf = enc.Encode(buf, key+"."+k, v[k])
+		}
+		return buf
+
+	case map[string]string:
+		if len(v) == 0 {
+			return enc.encodeRaw(buf, key, `{}`)
+		}
+		keys := make([]string, 0, len(v))
+		for k := range v {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			buf = enc.EncodeString(buf, key+"."+k, v[k])
+		}
+		return buf
+
+	case json.Marshaler:
+		data, err := v.MarshalJSON()
+		if err != nil {
+			return enc.EncodeString(buf, key, fmt.Sprintf("JSONError: %s", err.Error()))
+		}
+		return enc.EncodeString(buf, key, string(data))
+
+	default:
+		return enc.encodeReflect(buf, key, reflect.ValueOf(value))
+	}
+}
+
+func (enc *LogfmtEncoder) encodeReflect(buf []byte, key string, v reflect.Value) []byte {
+	switch v.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		if v.IsNil() {
+			return enc.encodeRaw(buf, key, `null`)
+		}
+
+	case reflect.Slice, reflect.Array:
+		if v.Kind() == reflect.Slice && v.IsNil() {
+			return enc.encodeRaw(buf, key, `null`)
+		} else if v.Len() == 0 {
+			return enc.encodeRaw(buf, key, `[]`)
+		}
+
+		for i, _len := 0, v.Len(); i < _len; i++ {
+			buf = enc.Encode(buf, key+"."+strconv.Itoa(i), v.Index(i).Interface())
+		}
+		return buf
+
+	case reflect.Map:
+		if v.Type().Key().Kind() != reflect.String {
+			break
+		} else if v.IsNil() {
+			return enc.encodeRaw(buf, key, `null`)
+		} else if v.Len() == 0 {
+			return enc.encodeRaw(buf, key, `{}`)
+		}
+
+		keys := v.MapKeys()
+		sort.Sort(mapKeys(keys))
+		for _, k := range keys {
+			buf = enc.Encode(buf, key+"."+k.String(), v.MapIndex(k).Interface())
+		}
+		return buf
+	}
+
+	data, err := json.Marshal(v.Interface())
+	if err != nil {
+		return enc.EncodeString(buf, key, fmt.Sprintf("JSONError: %s", err.Error()))
+	}
+	return enc.EncodeString(buf, key, string(data))
+}
+
+type mapKeys []reflect.Value
+
+func (ks mapKeys) Len() int           { return len(ks) }
+func (ks mapKeys) Less(i, j int) bool { return ks[i].String() < ks[j].String() }
+func (ks mapKeys) Swap(i, j int)      { ks[i], ks[j] = ks[j], ks[i] }
+
+// End implements the interface Encoder.
+func (enc *LogfmtEncoder) End(buf []byte, msg string) []byte {
+	// Msg
+	if len(enc.MsgKey) > 0 {
+		buf = appendLogfmtKey(buf, enc.MsgKey)
+		buf = appendLogfmtString(buf, msg)
+	} else if _len := len(buf) - 1; _len >= 0 && buf[_len] == ' ' {
+		buf = buf[:_len]
+	}
+
+	// Newline
+	if enc.Newline {
+		buf = append(buf, '\n')
+	}
+
+	return buf
+}
+
+func (enc *LogfmtEncoder) appendTime(dst []byte, t time.Time) []byte {
+	if enc.TimeFormatFunc == nil {
+		return t.AppendFormat(dst, time.RFC3339Nano)
+	}
+	return enc.TimeFormatFunc(dst, t)
+}
+
+func (enc *LogfmtEncoder) encodeRaw(dst []byte, key, value string) []byte {
+	dst = appendLogfmtKey(dst, key)
+	dst = append(dst, value...)
+	return append(dst, ' ')
+}
+
+/// ----------------------------------------------------------------------- ///
+
+// EncodeInt implements the interface IntEncoder.
+func (enc *LogfmtEncoder) EncodeInt(dst []byte, key string, value int) []byte {
+	dst = appendLogfmtKey(dst, key)
+	dst = strconv.AppendInt(dst, int64(value), 10)
+	return append(dst, ' ')
+}
+
+// EncodeInt64 implements the interface Int64Encoder.
+func (enc *LogfmtEncoder) EncodeInt64(dst []byte, key string, value int64) []byte {
+	dst = appendLogfmtKey(dst, key)
+	dst = strconv.AppendInt(dst, value, 10)
+	return append(dst, ' ')
+}
+
+// EncodeUint implements the interface UintEncoder.
+func (enc *LogfmtEncoder) EncodeUint(dst []byte, key string, value uint) []byte {
+	dst = appendLogfmtKey(dst, key)
+	dst = strconv.AppendUint(dst, uint64(value), 10)
+	return append(dst, ' ')
+}
+
+// EncodeUint64 implements the interface Uint64Encoder.
+func (enc *LogfmtEncoder) EncodeUint64(dst []byte, key string, value uint64) []byte {
+	dst = appendLogfmtKey(dst, key)
+	dst = strconv.AppendUint(dst, value, 10)
+	return append(dst, ' ')
+}
+
+// EncodeFloat64 implements the interface Float64Encoder.
+func (enc *LogfmtEncoder) EncodeFloat64(dst []byte, key string, value float64) []byte {
+	dst = appendLogfmtKey(dst, key)
+	dst = strconv.AppendFloat(dst, value, 'f', -1, 64)
+	return append(dst, ' ')
+}
+
+// EncodeBool implements the interface BoolEncoder.
+func (enc *LogfmtEncoder) EncodeBool(dst []byte, key string, value bool) []byte {
+	dst = appendLogfmtKey(dst, key)
+	dst = strconv.AppendBool(dst, value)
+	return append(dst, ' ')
+}
+
+// EncodeString implements the interface StringEncoder.
+func (enc *LogfmtEncoder) EncodeString(dst []byte, key string, value string) []byte {
+	dst = appendLogfmtKey(dst, key)
+	dst = appendLogfmtString(dst, value)
+	return append(dst, ' ')
+}
+
+// EncodeTime implements the interface TimeEncoder.
+func (enc *LogfmtEncoder) EncodeTime(dst []byte, key string, value time.Time) []byte {
+	dst = appendLogfmtKey(dst, key)
+	dst = enc.appendTime(dst, value)
+	return append(dst, ' ')
+}
+
+// EncodeDuration implements the interface DurationEncoder.
+func (enc *LogfmtEncoder) EncodeDuration(dst []byte, key string, value time.Duration) []byte {
+	dst = appendLogfmtKey(dst, key)
+	dst = append(dst, value.String()...)
+	return append(dst, ' ')
+}
+
+// EncodeStringSlice implements the interface StringSliceEncoder,
+// which flattens the elements as "key.0", "key.1", etc.
+func (enc *LogfmtEncoder) EncodeStringSlice(dst []byte, key string, value []string) []byte {
+	if len(value) == 0 {
+		return enc.encodeRaw(dst, key, `[]`)
+	}
+
+	for i, v := range value {
+		dst = enc.EncodeString(dst, key+"."+strconv.Itoa(i), v)
+	}
+	return dst
+}
+
+/// ----------------------------------------------------------------------- ///
+
+// appendLogfmtKey appends the key and '=' into dst, and the invalid
+// characters of the key are replaced with '_'.
+func appendLogfmtKey(dst []byte, key string) []byte {
+	switch {
+	case len(key) == 0:
+		dst = append(dst, '_')
+
+	case needLogfmtQuote(key):
+		for _, r := range key {
+			if isLogfmtSpecial(r) {
+				dst = append(dst, '_')
+			} else {
+				dst = append(dst, string(r)...)
+			}
+		}
+
+	default:
+		dst = append(dst, key...)
+	}
+	return append(dst, '=')
+}
+
+// appendLogfmtString appends the string s into dst, which is quoted
+// as the JSON string if necessary.
+func appendLogfmtString(dst []byte, s string) []byte {
+	if len(s) == 0 || needLogfmtQuote(s) {
+		return kvjson.AppendJSONString(dst, s)
+	}
+	return append(dst, s...)
+}
+
+func needLogfmtQuote(s string) bool {
+	for _, r := range s {
+		if isLogfmtSpecial(r) {
+			return true
+		}
+	}
+	return false
+}
+
+func isLogfmtSpecial(r rune) bool {
+	return r <= ' ' || r == '=' || r == '"' || r == 0x7f || r == utf8.RuneError
+}
@@ -0,0 +1,73 @@
+// Copyright 2026 xgfone
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package encoder
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestLogfmtEncoder(t *testing.T) {
+	now := time.Date(2026, time.January, 2, 3, 4, 5, 0, time.UTC)
+	defer func(f func() time.Time) { Now = f }(Now)
+	Now = func() time.Time { return now }
+
+	type point struct{ X, Y int }
+
+	enc := NewLogfmtEncoder()
+	buf := enc.Start(nil, "a.b", "info")
+	buf = enc.EncodeInt(buf, "k1", -1)
+	buf = enc.EncodeInt64(buf, "k2", 2)
+	buf = enc.EncodeUint(buf, "k3", 3)
+	buf = enc.EncodeUint64(buf, "k4", 4)
+	buf = enc.EncodeFloat64(buf, "k5", 5.5)
+	buf = enc.EncodeBool(buf, "k6", true)
+	buf = enc.EncodeString(buf, "k7", "a value")
+	buf = enc.EncodeTime(buf, "k8", now)
+	buf = enc.EncodeDuration(buf, "k9", time.Second)
+	buf = enc.EncodeStringSlice(buf, "k10", []string{"a", ""})
+	buf = enc.Encode(buf, "nil", nil)
+	buf = enc.Encode(buf, "err", errors.New(`say "hi"`))
+	buf = enc.Encode(buf, "bytes", []byte("a=b"))
+	buf = enc.Encode(buf, "any", []interface{}{1, "x"})
+	buf = enc.Encode(buf, "map", map[string]interface{}{"b": 2, "a": map[string]string{"c": "3"}})
+	buf = enc.Encode(buf, "ints", []int{})
+	buf = enc.Encode(buf, "rmap", map[string]int{"y": 2, "x": 1})
+	buf = enc.Encode(buf, "struct", point{1, 2})
+	buf = enc.Encode(buf, "user", testUser{Name: "xgfone", Roles: testRoles{"admin"}})
+	buf = enc.Encode(buf, "bad key", "line1\nline2")
+	buf = enc.Encode(buf, "", "\xff")
+	buf = enc.End(buf, "hello world")
+
+	expect := `t=2026-01-02T03:04:05Z lvl=info logger=a.b k1=-1 k2=2 k3=3 k4=4 k5=5.5 k6=true ` +
+		`k7="a value" k8=2026-01-02T03:04:05Z k9=1s k10.0=a k10.1="" nil=null err="say \"hi\"" ` +
+		`bytes="a=b" any.0=1 any.1=x map.a.c=3 map.b=2 ints=[] rmap.x=1 rmap.y=2 ` +
+		`struct="{\"X\":1,\"Y\":2}" user.name=xgfone user.age=0 user.roles.0=admin ` +
+		`bad_key="line1\nline2" _="\ufffd" msg="hello world"` + "\n"
+	if s := string(buf); s != expect {
+		t.Errorf("expect '%s', but got '%s'", expect, s)
+	}
+
+	enc.TimeKey = ""
+	enc.MsgKey = ""
+	enc.Newline = false
+	buf = enc.Start(buf[:0], "", "warn")
+	buf = enc.EncodeString(buf, "k", "v")
+	buf = enc.End(buf, "msg")
+	if expect = `lvl=warn k=v`; string(buf) != expect {
+		t.Errorf("expect '%s', but got '%s'", expect, string(buf))
+	}
+}
@@ -0,0 +1,315 @@
+// Copyright 2026 xgfone
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package encoder
+
+import (
+	"strconv"
+	"time"
+)
+
+// ObjectMarshaler is used by the type to marshal itself as the object
+// by adding its fields, which is independent of the output format.
+type ObjectMarshaler interface {
+	MarshalLogObject(enc FieldEncoder)
+}
+
+// ArrayMarshaler is used by the type to marshal itself as the array
+// by appending its elements, which is independent of the output format.
+type ArrayMarshaler interface {
+	MarshalLogArray(enc ElemEncoder)
+}
+
+// FieldEncoder is used to add the fields of the object.
+type FieldEncoder interface {
+	AddAny(key string, value interface{})
+	AddString(key string, value string)
+	AddInt64(key string, value int64)
+	AddUint64(key string, value uint64)
+	AddFloat64(key string, value float64)
+	AddBool(key string, value bool)
+	AddTime(key string, value time.Time)
+	AddObject(key string, value ObjectMarshaler)
+	AddArray(key string, value ArrayMarshaler)
+}
+
+// ElemEncoder is used to append the elements of the array.
+type ElemEncoder interface {
+	AppendAny(value interface{})
+	AppendString(value string)
+	AppendInt64(value int64)
+	AppendUint64(value uint64)
+	AppendFloat64(value float64)
+	AppendBool(value bool)
+	AppendTime(value time.Time)
+	AppendObject(value ObjectMarshaler)
+	AppendArray(value ArrayMarshaler)
+}
+
+// KVEncoder is used to encode the key and the value of any type.
+type KVEncoder interface {
+	Encode(dst []byte, key string, value interface{}) []byte
+}
+
+// EncodeObject encodes the object marshaled by value with the key
+// into the buffer dst by enc.
+//
+// If enc implements both the interfaces ObjectEncoder and ArrayEncoder,
+// the object is encoded as the nested object. Or, the keys of the fields
+// are flattened with the prefix key+".", such as "key.subkey", and the
+// typed encoders, such as StringEncoder, are used if enc implements them.
+func EncodeObject(dst []byte, enc KVEncoder, key string, value ObjectMarshaler) []byte {
+	f := newFieldEncoder(dst, enc)
+	f.AddObject(key, value)
+	return f.dst
+}
+
+// EncodeArray is the same as EncodeObject, but encodes the array marshaled
+// by value, whose indexes are used as the keys of the flattened elements,
+// such as "key.0", "key.1", etc.
+func EncodeArray(dst []byte, enc KVEncoder, key string, value ArrayMarshaler) []byte {
+	f := newFieldEncoder(dst, enc)
+	f.AddArray(key, value)
+	return f.dst
+}
+
+// EncodeElem encodes the value implementing ObjectMarshaler or ArrayMarshaler
+// as the element of the nested array by enc, which must implement both
+// the interfaces ObjectEncoder and ArrayEncoder. If value does not implement
+// them, return dst and false.
+func EncodeElem(dst []byte, enc KVEncoder, value interface{}) ([]byte, bool) {
+	switch v := value.(type) {
+	case ObjectMarshaler:
+		f := newFieldEncoder(dst, enc)
+		f.AppendObject(v)
+		return f.dst, true
+
+	case ArrayMarshaler:
+		f := newFieldEncoder(dst, enc)
+		f.AppendArray(v)
+		return f.dst, true
+
+	default:
+		return dst, false
+	}
+}
+
+/// ----------------------------------------------------------------------- ///
+
+type fieldEncoder struct {
+	enc KVEncoder
+	dst []byte
+
+	// For the nested encoder
+	obj ObjectEncoder
+	arr ArrayEncoder
+
+	// For the flattened fields
+	prefix string
+	index  int
+}
+
+func newFieldEncoder(dst []byte, enc KVEncoder) *fieldEncoder {
+	f := &fieldEncoder{enc: enc, dst: dst}
+	if obj, ok := enc.(ObjectEncoder); ok {
+		if arr, ok := enc.(ArrayEncoder); ok {
+			f.obj, f.arr = obj, arr
+		}
+	}
+	return f
+}
+
+func (f *fieldEncoder) nested() bool { return f.obj != nil }
+
+func (f *fieldEncoder) nextKey() (key string) {
+	key = strconv.Itoa(f.index)
+	f.index++
+	return
+}
+
+func (f *fieldEncoder) AddAny(key string, value interface{}) {
+	switch v := value.(type) {
+	case ObjectMarshaler:
+		f.AddObject(key, v)
+	case ArrayMarshaler:
+		f.AddArray(key, v)
+	default:
+		f.dst = f.enc.Encode(f.dst, f.prefix+key, value)
+	}
+}
+
+func (f *fieldEncoder) AddString(key string, value string) {
+	if enc, ok := f.enc.(StringEncoder); ok {
+		f.dst = enc.EncodeString(f.dst, f.prefix+key, value)
+	} else {
+		f.dst = f.enc.Encode(f.dst, f.prefix+key, value)
+	}
+}
+
+func (f *fieldEncoder) AddInt64(key string, value int64) {
+	if enc, ok := f.enc.(Int64Encoder); ok {
+		f.dst = enc.EncodeInt64(f.dst, f.prefix+key, value)
+	} else {
+		f.dst = f.enc.Encode(f.dst, f.prefix+key, value)
+	}
+}
+
+func (f *fieldEncoder) AddUint64(key string, value uint64) {
+	if enc, ok := f.enc.(Uint64Encoder); ok {
+		f.dst = enc.EncodeUint64(f.dst, f.prefix+key, value)
+	} else {
+		f.dst = f.enc.Encode(f.dst, f.prefix+key, value)
+	}
+}
+
+func (f *fieldEncoder) AddFloat64(key string, value float64) {
+	if enc, ok := f.enc.(Float64Encoder); ok {
+		f.dst = enc.EncodeFloat64(f.dst, f.prefix+key, value)
+	} else {
+		f.dst = f.enc.Encode(f.dst, f.prefix+key, value)
+	}
+}
+
+func (f *fieldEncoder) AddBool(key string, value bool) {
+	if enc, ok := f.enc.(BoolEncoder); ok {
+		f.dst = enc.EncodeBool(f.dst, f.prefix+key, value)
+	} else {
+		f.dst = f.enc.Encode(f.dst, f.prefix+key, value)
+	}
+}
+
+func (f *fieldEncoder) AddTime(key string, value time.Time) {
+	if enc, ok := f.enc.(TimeEncoder); ok {
+		f.dst = enc.EncodeTime(f.dst, f.prefix+key, value)
+	} else {
+		f.dst = f.enc.Encode(f.dst, f.prefix+key, value)
+	}
+}
+
+func (f *fieldEncoder) AddObject(key string, value ObjectMarshaler) {
+	if f.nested() {
+		f.dst = f.obj.EncodeObjectBegin(f.dst, key)
+		value.MarshalLogObject(f)
+		f.dst = f.obj.EncodeObjectEnd(f.dst)
+	} else {
+		prefix := f.prefix
+		f.prefix = prefix + key + "."
+		value.MarshalLogObject(f)
+		f.prefix = prefix
+	}
+}
+
+func (f *fieldEncoder) AddArray(key string, value ArrayMarshaler) {
+	index := f.index
+	if f.nested() {
+		f.dst = f.arr.EncodeArrayBegin(f.dst, key)
+		f.index = 0
+		value.MarshalLogArray(f)
+		f.dst = f.arr.EncodeArrayEnd(f.dst)
+	} else {
+		prefix := f.prefix
+		f.prefix, f.index = prefix+key+".", 0
+		value.MarshalLogArray(f)
+		f.prefix = prefix
+	}
+	f.index = index
+}
+
+/// ----------------------------------------------------------------------- ///
+
+func (f *fieldEncoder) AppendAny(value interface{}) {
+	switch v := value.(type) {
+	case ObjectMarshaler:
+		f.AppendObject(v)
+	case ArrayMarshaler:
+		f.AppendArray(v)
+	default:
+		if f.nested() {
+			f.dst = f.arr.EncodeArrayElem(f.dst, value)
+		} else {
+			f.AddAny(f.nextKey(), value)
+		}
+	}
+}
+
+func (f *fieldEncoder) AppendString(value string) {
+	if f.nested() {
+		f.dst = f.arr.EncodeArrayElem(f.dst, value)
+	} else {
+		f.AddString(f.nextKey(), value)
+	}
+}
+
+func (f *fieldEncoder) AppendInt64(value int64) {
+	if f.nested() {
+		f.dst = f.arr.EncodeArrayElem(f.dst, value)
+	} else {
+		f.AddInt64(f.nextKey(), value)
+	}
+}
+
+func (f *fieldEncoder) AppendUint64(value uint64) {
+	if f.nested() {
+		f.dst = f.arr.EncodeArrayElem(f.dst, value)
+	} else {
+		f.AddUint64(f.nextKey(), value)
+	}
+}
+
+func (f *fieldEncoder) AppendFloat64(value float64) {
+	if f.nested() {
+		f.dst = f.arr.EncodeArrayElem(f.dst, value)
+	} else {
+		f.AddFloat64(f.nextKey(), value)
+	}
+}
+
+func (f *fieldEncoder) AppendBool(value bool) {
+	if f.nested() {
+		f.dst = f.arr.EncodeArrayElem(f.dst, value)
+	} else {
+		f.AddBool(f.nextKey(), value)
+	}
+}
+
+func (f *fieldEncoder) AppendTime(value time.Time) {
+	if f.nested() {
+		f.dst = f.arr.EncodeArrayElem(f.dst, value)
+	} else {
+		f.AddTime(f.nextKey(), value)
+	}
+}
+
+func (f *fieldEncoder) AppendObject(value ObjectMarshaler) {
+	if f.nested() {
+		f.dst = f.arr.EncodeElemObjectBegin(f.dst)
+		value.MarshalLogObject(f)
+		f.dst = f.obj.EncodeObjectEnd(f.dst)
+	} else {
+		f.AddObject(f.nextKey(), value)
+	}
+}
+
+func (f *fieldEncoder) AppendArray(value ArrayMarshaler) {
+	if f.nested() {
+		index := f.index
+		f.dst = f.arr.EncodeElemArrayBegin(f.dst)
+		f.index = 0
+		value.MarshalLogArray(f)
+		f.dst = f.arr.EncodeArrayEnd(f.dst)
+		f.index = index
+	} else {
+		f.AddArray(f.nextKey(), value)
+	}
+}
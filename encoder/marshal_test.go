@@ -0,0 +1,90 @@
+// Copyright 2026 xgfone
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package encoder
+
+import (
+	"testing"
+	"time"
+)
+
+type testUser struct {
+	Name  string
+	Age   int64
+	Roles testRoles
+}
+
+func (u testUser) MarshalLogObject(enc FieldEncoder) {
+	enc.AddString("name", u.Name)
+	enc.AddInt64("age", u.Age)
+	enc.AddArray("roles", u.Roles)
+}
+
+type testRoles []string
+
+func (rs testRoles) MarshalLogArray(enc ElemEncoder) {
+	for _, r := range rs {
+		enc.AppendString(r)
+	}
+}
+
+type testValues struct{}
+
+func (testValues) MarshalLogArray(enc ElemEncoder) {
+	enc.AppendAny(nil)
+	enc.AppendUint64(1)
+	enc.AppendFloat64(2.5)
+	enc.AppendBool(true)
+	enc.AppendTime(time.Date(2026, time.January, 2, 3, 4, 5, 0, time.UTC))
+	enc.AppendObject(testUser{Name: "a"})
+	enc.AppendArray(testRoles{"b"})
+}
+
+// kvEncoder is a simple encoder only implementing KVEncoder.
+type kvEncoder struct{ JSONEncoder }
+
+func (enc kvEncoder) Encode(dst []byte, key string, value interface{}) []byte {
+	return enc.JSON.EncodeKV(dst, key, value)
+}
+
+func TestMarshaler(t *testing.T) {
+	enc := NewJSONEncoder()
+	enc.TimeKey = ""
+
+	user := testUser{Name: "xgfone", Age: 18, Roles: testRoles{"admin", "dev"}}
+	buf := enc.Start(nil, "", "info")
+	buf = enc.Encode(buf, "user", user)
+	buf = enc.Encode(buf, "values", testValues{})
+	buf = enc.EncodeArrayBegin(buf, "users")
+	buf = enc.EncodeArrayElem(buf, user)
+	buf = enc.EncodeArrayElem(buf, testRoles{})
+	buf = enc.EncodeArrayEnd(buf)
+	buf = enc.End(buf, "msg")
+
+	expect := `{"lvl":"info","user":{"name":"xgfone","age":18,"roles":["admin","dev"]},` +
+		`"values":[null,1,2.5,true,"2026-01-02T03:04:05Z",{"name":"a","age":0,"roles":[]},["b"]],` +
+		`"users":[{"name":"xgfone","age":18,"roles":["admin","dev"]},[]],"msg":"msg"}` + "\n"
+	if s := string(buf); s != expect {
+		t.Errorf("expect '%s', but got '%s'", expect, s)
+	}
+
+	buf = EncodeObject(buf[:0], kvEncoder{}, "user", user)
+	buf = EncodeArray(buf, kvEncoder{}, "values", testValues{})
+	expect = `"user.name":"xgfone","user.age":18,"user.roles.0":"admin","user.roles.1":"dev",` +
+		`"values.0":null,"values.1":1,"values.2":2.5,"values.3":true,"values.4":"2026-01-02T03:04:05Z",` +
+		`"values.5.name":"a","values.5.age":0,"values.6.0":"b",`
+	if s := string(buf); s != expect {
+		t.Errorf("expect '%s', but got '%s'", expect, s)
+	}
+}
@@ -0,0 +1,53 @@
+// Copyright 2026 xgfone
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package log
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestLoggerWithGroup(t *testing.T) {
+	buf := bytes.NewBuffer(nil)
+	logger := New("").WithWriter(buf).WithEncoder(newTestEncoder()).
+		WithHooks(Caller("caller")).WithContext("id", 1).
+		WithGroup("http").WithContext("id", 2).
+		WithGroup("req").WithContext("method", "GET")
+
+	if groups := logger.Groups(); len(groups) != 2 || groups[0] != "http" || groups[1] != "req" {
+		t.Errorf("unexpected groups %v", groups)
+	}
+
+	logger.Info().Kv("status", 200).Print("msg1")
+	logger.WithGroup("").Info().Print("msg2")
+
+	logger = logger.WithEncoder(flatEncoder{newTestEncoder()})
+	logger.Info().Kv("status", 200).Dict("resp", func(d *Emitter) {
+		d.Int("size", 10)
+	}).Print("msg3")
+
+	logger = logger.WithEncoder(newTestEncoder())
+	logger.Info().Print("msg4")
+
+	expects := []string{
+		`{"lvl":"info","id":1,"caller":"group_test.go:TestLoggerWithGroup:34","http":{"id":2,"req":{"method":"GET","status":200}},"msg":"msg1"}`,
+		`{"lvl":"info","id":1,"caller":"group_test.go:TestLoggerWithGroup:35","http":{"id":2,"req":{"method":"GET"}},"msg":"msg2"}`,
+		`{"lvl":"info","id":1,"caller":"group_test.go:TestLoggerWithGroup:38","http.id":2,"http.req.method":"GET","http.req.status":200,"http.req.resp.size":10,"msg":"msg3"}`,
+		`{"lvl":"info","id":1,"caller":"group_test.go:TestLoggerWithGroup:43","http":{"id":2,"req":{"method":"GET"}},"msg":"msg4"}`,
+		``,
+	}
+	testStrings(t, "group", expects, strings.Split(buf.String(), "\n"))
+}
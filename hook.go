@@ -56,7 +56,10 @@ var CallerFormatFunc = func(file, name string, line int) string {
 // like "File:FunctionName:Line".
 func Caller(key string) Hook {
 	return HookFunc(func(e *Emitter, name string, level, depth int) {
-		if pc, file, line, ok := runtime.Caller(depth + 1); ok {
+		if pc := e.PC(); pc != 0 {
+			frame, _ := runtime.CallersFrames([]uintptr{pc}).Next()
+			e.Kv(key, CallerFormatFunc(frame.File, frame.Function, frame.Line))
+		} else if pc, file, line, ok := runtime.Caller(depth + 1); ok {
 			f := runtime.FuncForPC(pc)
 			e.Kv(key, CallerFormatFunc(file, f.Name(), line))
 		}
@@ -0,0 +1,37 @@
+// Copyright 2026 xgfone
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package term provides the helper to detect the terminal, which is shared
+// by the encoders and the writers without depending on each other.
+package term
+
+import (
+	"io"
+	"os"
+)
+
+// IsTerminal reports whether the innermost writer unwrapped by the method
+// UnwrapWriter is an *os.File referring to a terminal.
+func IsTerminal(w io.Writer) bool {
+	for {
+		if ww, ok := w.(interface{ UnwrapWriter() io.Writer }); ok {
+			w = ww.UnwrapWriter()
+		} else {
+			break
+		}
+	}
+
+	file, ok := w.(*os.File)
+	return ok && file != nil && isTerminal(file)
+}
@@ -17,6 +17,7 @@ package log
 import (
 	"fmt"
 	"os"
+	"strconv"
 	"strings"
 	"sync/atomic"
 )
@@ -167,6 +168,31 @@ func ParseLevel(level string, defaultLevel ...int) int {
 	}
 }
 
+// ParseFormattedLevel parses the level string formatted by the default
+// FormatLevel, such as "info" and "info5", which is case insensitive.
+//
+// If the level string is unknown, return defaultLevel instead.
+func ParseFormattedLevel(level string, defaultLevel int) int {
+	level = strings.ToLower(level)
+	for _, base := range []string{"trace", "debug", "info", "warn",
+		"error", "alert", "panic", "fatal"} {
+		if !strings.HasPrefix(level, base) {
+			continue
+		}
+
+		lvl := ParseLevel(base)
+		if offset := level[len(base):]; offset != "" {
+			n, err := strconv.Atoi(offset)
+			if err != nil || !LevelIsValid(lvl+n) {
+				return defaultLevel
+			}
+			lvl += n
+		}
+		return lvl
+	}
+	return defaultLevel
+}
+
 // Enabled reports whether the given level is enabled.
 func (l Logger) Enabled(level int) bool {
 	checkLevel(level)
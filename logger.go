@@ -35,6 +35,17 @@ type Logger struct {
 	hooks []Hook
 	ctxs  []interface{}
 	ctx   []byte
+
+	// Group Context
+	groups []loggerGroup
+	prefix string // The key prefix of the flattened groups.
+	gctx   int    // The offset of the first group in ctx.
+	gclose int    // The number of the nested groups to be closed.
+}
+
+type loggerGroup struct {
+	name  string
+	index int // The index of the first context in ctxs after the group.
 }
 
 // New creates a new root logger, which encodes the log message as JSON
@@ -60,6 +71,11 @@ func (l Logger) Clone() Logger {
 		hooks: append([]Hook{}, l.hooks...),
 		ctxs:  append([]interface{}{}, l.ctxs...),
 		ctx:   append([]byte{}, l.ctx...),
+
+		groups: append([]loggerGroup{}, l.groups...),
+		prefix: l.prefix,
+		gctx:   l.gctx,
+		gclose: l.gclose,
 	}
 }
 
@@ -136,7 +152,7 @@ func (l Logger) Contexts() (kvs []interface{}) { return l.ctxs }
 // WithContext returns a new logger that appends the key-value context.
 func (l Logger) WithContext(key string, value interface{}) Logger {
 	l = l.Clone()
-	l.ctx = l.Output.encoder.Encode(l.ctx, key, value)
+	l.ctx = l.Output.encoder.Encode(l.ctx, l.prefix+key, value)
 	l.ctxs = append(l.ctxs, key, value)
 	return l
 }
@@ -155,11 +171,59 @@ func (l *Logger) appendContexts(kvs ...interface{}) {
 	}
 
 	for i := 0; i < _len; i += 2 {
-		l.ctx = l.Output.encoder.Encode(l.ctx, kvs[i].(string), kvs[i+1])
+		l.ctx = l.Output.encoder.Encode(l.ctx, l.prefix+kvs[i].(string), kvs[i+1])
 	}
 	l.ctxs = append(l.ctxs, kvs...)
 }
 
+// Groups returns the names of all the groups opened by WithGroup.
+func (l Logger) Groups() []string {
+	if len(l.groups) == 0 {
+		return nil
+	}
+
+	names := make([]string, len(l.groups))
+	for i, group := range l.groups {
+		names[i] = group.name
+	}
+	return names
+}
+
+// WithGroup returns a new logger that opens a group named name,
+// then all the subsequent key-value contexts of the logger and fields
+// of the emitters are written into the nested object named name,
+// which is closed before the log message is written. But the fields
+// added by the hooks are not in any group.
+//
+// If the encoder does not implement both the interfaces encoder.ObjectEncoder
+// and encoder.ArrayEncoder, the keys of the subsequent fields are flattened
+// with the prefix name+".", such as "name.key".
+//
+// If name is empty, return the logger itself.
+func (l Logger) WithGroup(name string) Logger {
+	if len(name) == 0 {
+		return l
+	}
+
+	l = l.Clone()
+	l.openGroup(name)
+	return l
+}
+
+func (l *Logger) openGroup(name string) {
+	if len(l.groups) == 0 {
+		l.gctx = len(l.ctx)
+	}
+
+	l.groups = append(l.groups, loggerGroup{name: name, index: len(l.ctxs)})
+	if l.Output.encoder.nested {
+		l.ctx = l.Output.encoder.EncodeObjectBegin(l.ctx, name)
+		l.gclose++
+	} else {
+		l.prefix += name + "."
+	}
+}
+
 // Write implements the interface io.Writer.
 func (l Logger) Write(p []byte) (n int, err error) {
 	n = len(p)
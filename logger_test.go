@@ -21,6 +21,7 @@ import (
 	"testing"
 
 	jencoder "github.com/xgfone/go-log/encoder"
+	"github.com/xgfone/go-log/writer"
 )
 
 func newTestEncoder() Encoder {
@@ -145,6 +146,20 @@ func TestLevelDisable(t *testing.T) {
 	}
 }
 
+func TestParseFormattedLevel(t *testing.T) {
+	for level := LvlTrace; level < LvlDisable; level++ {
+		if lvl := ParseFormattedLevel(formatLevel(level), -1); lvl != level {
+			t.Errorf("expect level %d, but got %d", level, lvl)
+		}
+	}
+
+	for _, level := range []string{"", "unknown", "infox", "fatal9"} {
+		if lvl := ParseFormattedLevel(level, LvlInfo); lvl != LvlInfo {
+			t.Errorf("%s: expect the default level, but got %d", level, lvl)
+		}
+	}
+}
+
 func TestSampler(t *testing.T) {
 	buf := bytes.NewBuffer(nil)
 	sample := func(name string, lvl int) bool { return lvl > LvlWarn }
@@ -172,3 +187,12 @@ func TestSampler(t *testing.T) {
 	}
 	testStrings(t, "sampler", expects, strings.Split(buf.String(), "\n"))
 }
+
+func TestWriterLevels(t *testing.T) {
+	if writer.LevelError != LvlError {
+		t.Errorf("expect writer.LevelError %d, but got %d", LvlError, writer.LevelError)
+	}
+	if writer.LevelPanic != LvlPanic {
+		t.Errorf("expect writer.LevelPanic %d, but got %d", LvlPanic, writer.LevelPanic)
+	}
+}
@@ -0,0 +1,8 @@
+module github.com/xgfone/go-log/logr
+
+go 1.18
+
+require (
+	github.com/go-logr/logr v1.4.2
+	github.com/xgfone/go-log v0.0.0-20261016115132-72aa03d96aa9
+)
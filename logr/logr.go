@@ -0,0 +1,133 @@
+// Copyright 2026 xgfone
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build go1.18
+// +build go1.18
+
+// Package logr provides a logr.LogSink implementation based on
+// the key-value logger.
+package logr
+
+import (
+	"github.com/go-logr/logr"
+	"github.com/xgfone/go-log"
+)
+
+var (
+	_ logr.LogSink                = &LogSink{}
+	_ logr.CallDepthLogSink       = &LogSink{}
+	_ logr.CallStackHelperLogSink = &LogSink{}
+	_ Underlier                   = &LogSink{}
+)
+
+// Underlier is implemented by the LogSink to expose the inner logger.
+type Underlier interface {
+	GetUnderlying() log.Logger
+}
+
+// DefaultVLevel is the default function to convert the logr V-level
+// to the level of the key-value logger, which goes downward from log.LvlInfo,
+// that's, V(0) is log.LvlInfo, V(1) is log.LvlInfo-1, and so on,
+// and the result is not less than log.LvlTrace.
+func DefaultVLevel(v int) int {
+	if level := log.LvlInfo - v; level > log.LvlTrace {
+		return level
+	}
+	return log.LvlTrace
+}
+
+// New is equal to logr.New(NewLogSink(logger)).
+func New(logger log.Logger) logr.Logger {
+	return logr.New(NewLogSink(logger))
+}
+
+// LogSink is a logr sink based on the key-value logger.
+type LogSink struct {
+	logger log.Logger
+	vlevel func(int) int
+	depth  int
+}
+
+// NewLogSink returns a new logr sink based on the key-value logger,
+// which uses DefaultVLevel to convert the V-level.
+func NewLogSink(logger log.Logger) *LogSink {
+	return &LogSink{logger: logger, vlevel: DefaultVLevel}
+}
+
+// WithVLevel returns a new LogSink with the function to convert
+// the logr V-level to the level of the key-value logger.
+//
+// If vlevel is nil, use DefaultVLevel instead.
+func (s *LogSink) WithVLevel(vlevel func(v int) int) *LogSink {
+	if vlevel == nil {
+		vlevel = DefaultVLevel
+	}
+
+	ns := s.clone()
+	ns.vlevel = vlevel
+	return ns
+}
+
+func (s *LogSink) clone() *LogSink {
+	ns := *s
+	return &ns
+}
+
+// GetUnderlying implements the interface Underlier.
+func (s *LogSink) GetUnderlying() log.Logger { return s.logger }
+
+// Init implements the interface logr.LogSink.
+func (s *LogSink) Init(info logr.RuntimeInfo) { s.depth = info.CallDepth + 1 }
+
+// Enabled implements the interface logr.LogSink.
+func (s *LogSink) Enabled(level int) bool {
+	return s.logger.Enabled(s.vlevel(level))
+}
+
+// Info implements the interface logr.LogSink.
+func (s *LogSink) Info(level int, msg string, keysAndValues ...interface{}) {
+	s.logger.Level(s.vlevel(level), s.depth).Kvs(keysAndValues...).Printf(msg)
+}
+
+// Error implements the interface logr.LogSink.
+func (s *LogSink) Error(err error, msg string, keysAndValues ...interface{}) {
+	s.logger.Level(log.LvlError, s.depth).Kvs(keysAndValues...).Kv("err", err).Printf(msg)
+}
+
+// WithName implements the interface logr.LogSink.
+func (s *LogSink) WithName(name string) logr.LogSink {
+	ns := s.clone()
+	ns.logger = s.logger.WithName(name)
+	return ns
+}
+
+// WithValues implements the interface logr.LogSink.
+func (s *LogSink) WithValues(keysAndValues ...interface{}) logr.LogSink {
+	ns := s.clone()
+	ns.logger = s.logger.WithContexts(keysAndValues...)
+	return ns
+}
+
+// WithCallDepth implements the interface logr.CallDepthLogSink.
+func (s *LogSink) WithCallDepth(depth int) logr.LogSink {
+	ns := s.clone()
+	ns.depth += depth
+	return ns
+}
+
+// GetCallStackHelper implements the interface logr.CallStackHelperLogSink.
+//
+// The logger has no concept of the helper functions, so it returns
+// a no-op function and the stack depth should be adjusted by WithCallDepth.
+func (s *LogSink) GetCallStackHelper() func() { return func() {} }
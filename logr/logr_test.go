@@ -0,0 +1,105 @@
+// Copyright 2026 xgfone
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build go1.18
+// +build go1.18
+
+package logr
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/go-logr/logr"
+	"github.com/xgfone/go-log"
+	"github.com/xgfone/go-log/encoder"
+)
+
+func logIfErr(logger logr.Logger, err error, msg string, kvs ...interface{}) {
+	if err != nil {
+		logger.WithCallDepth(1).Error(err, msg, kvs...)
+	}
+}
+
+func logHelper(logger logr.Logger, msg string) {
+	helper, logger := logger.WithCallStackHelper()
+	helper()
+	logger.Info(msg)
+}
+
+func TestLogSink(t *testing.T) {
+	enc := encoder.NewJSONEncoder()
+	enc.TimeKey = ""
+
+	buf := bytes.NewBuffer(nil)
+	_logger := log.New("test").WithWriter(buf).WithEncoder(enc).
+		WithHooks(log.Caller("caller")).WithLevel(log.LvlInfo - 2)
+
+	logger := New(_logger)
+	logger.Info("msg1", "k1", "v1")
+	logger.V(2).Info("msg2")
+	logger.V(3).Info("msg3") // Disabled
+	logger.Error(errors.New("error"), "msg4", "k4", "v4")
+	logger.WithName("name").WithValues("k0", "v0").Info("msg5")
+	logIfErr(logger, errors.New("error"), "msg6")
+	logHelper(logger, "msg7")
+
+	if logger.V(3).Enabled() {
+		t.Error("expect V(3) to be disabled, but got enabled")
+	}
+	if s, ok := logger.GetSink().(Underlier); !ok {
+		t.Error("expect an Underlier, but not")
+	} else if name := s.GetUnderlying().Name(); name != "test" {
+		t.Errorf("expect logger name '%s', but got '%s'", "test", name)
+	}
+
+	const caller = `"caller":"logr_test.go:TestLogSink:`
+	expects := []string{
+		`{"lvl":"info","logger":"test",` + caller + `52","k1":"v1","msg":"msg1"}`,
+		`{"lvl":"debug18","logger":"test",` + caller + `53","msg":"msg2"}`,
+		`{"lvl":"error","logger":"test",` + caller + `55","k4":"v4","err":"error","msg":"msg4"}`,
+		`{"lvl":"info","logger":"test.name","k0":"v0",` + caller + `56","msg":"msg5"}`,
+		`{"lvl":"error","logger":"test",` + caller + `57","err":"error","msg":"msg6"}`,
+		`{"lvl":"info","logger":"test",` + caller + `58","msg":"msg7"}`,
+		``,
+	}
+
+	lines := strings.Split(buf.String(), "\n")
+	if len(expects) != len(lines) {
+		t.Fatalf("expect %d lines, but got %d: %v", len(expects), len(lines), lines)
+	}
+	for i, line := range expects {
+		if lines[i] != line {
+			t.Errorf("%d line: expect '%s', but got '%s'", i, line, lines[i])
+		}
+	}
+}
+
+func TestVLevel(t *testing.T) {
+	sink := NewLogSink(log.New("").WithLevel(log.LvlDebug)).
+		WithVLevel(func(v int) int { return log.LvlInfo - v*log.LvlDebug })
+
+	if !sink.Enabled(1) {
+		t.Error("expect V(1) to be enabled, but got disabled")
+	}
+	if sink.Enabled(2) {
+		t.Error("expect V(2) to be disabled, but got enabled")
+	}
+
+	if level := DefaultVLevel(100); level != log.LvlTrace {
+		t.Errorf("expect level %d, but got %d", log.LvlTrace, level)
+	}
+}
@@ -0,0 +1,204 @@
+// Copyright 2026 xgfone
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package log
+
+import (
+	"strconv"
+	"time"
+)
+
+// key returns the key with the prefix of the flattened nested fields.
+func (e *Emitter) key(key string) string {
+	if len(e.prefix) == 0 {
+		return key
+	}
+	return e.prefix + key
+}
+
+// Dict appends a nested object with the key into the log message,
+// whose fields are appended by the function f, and returns the emitter itself.
+//
+// The emitter passed to f is the emitter itself, which should only be used
+// to append the fields in f, not to emit the log.
+//
+// If the encoder does not implement both the interfaces encoder.ObjectEncoder
+// and encoder.ArrayEncoder, the nested fields are flattened by prefixing
+// their keys with key+".", such as "key.subkey".
+func (e *Emitter) Dict(key string, f func(d *Emitter)) *Emitter {
+	if e == nil {
+		return nil
+	}
+
+	prefix := e.prefix
+	if e.encoder.nested {
+		e.buffer = e.encoder.EncodeObjectBegin(e.buffer, e.key(key))
+		f(e)
+		e.buffer = e.encoder.EncodeObjectEnd(e.buffer)
+	} else {
+		e.prefix = e.key(key) + "."
+		f(e)
+		e.prefix = prefix
+	}
+	return e
+}
+
+// Array appends a nested array with the key into the log message,
+// whose elements are appended by the function f, and returns the emitter itself.
+//
+// If the encoder does not implement both the interfaces encoder.ObjectEncoder
+// and encoder.ArrayEncoder, the elements are flattened by prefixing
+// their indexes with key+".", such as "key.0", "key.1", etc.
+func (e *Emitter) Array(key string, f func(a *ArrayEmitter)) *Emitter {
+	if e == nil {
+		return nil
+	}
+
+	prefix, index := e.prefix, e.index
+	if e.encoder.nested {
+		e.buffer = e.encoder.EncodeArrayBegin(e.buffer, e.key(key))
+		f((*ArrayEmitter)(e))
+		e.buffer = e.encoder.EncodeArrayEnd(e.buffer)
+	} else {
+		e.prefix, e.index = e.key(key)+".", 0
+		f((*ArrayEmitter)(e))
+		e.prefix, e.index = prefix, index
+	}
+	return e
+}
+
+/// ----------------------------------------------------------------------- ///
+
+// ArrayEmitter is used to append the elements of the nested array,
+// which is created by Emitter.Array.
+type ArrayEmitter Emitter
+
+// nextKey returns the flattened key of the next element.
+func (a *ArrayEmitter) nextKey() (key string) {
+	key = a.prefix + strconv.Itoa(a.index)
+	a.index++
+	return
+}
+
+// Any appends an element with any value and returns the array emitter itself.
+func (a *ArrayEmitter) Any(value interface{}) *ArrayEmitter {
+	if a.encoder.nested {
+		a.buffer = a.encoder.EncodeArrayElem(a.buffer, value)
+	} else {
+		a.buffer = a.encoder.Encode(a.buffer, a.nextKey(), value)
+	}
+	return a
+}
+
+// Str appends an element typed string and returns the array emitter itself.
+func (a *ArrayEmitter) Str(value string) *ArrayEmitter {
+	if a.encoder.nested {
+		a.buffer = a.encoder.EncodeArrayElem(a.buffer, value)
+	} else {
+		a.buffer = a.encoder.EncodeString(a.buffer, a.nextKey(), value)
+	}
+	return a
+}
+
+// Int appends an element typed int and returns the array emitter itself.
+func (a *ArrayEmitter) Int(value int) *ArrayEmitter {
+	if a.encoder.nested {
+		a.buffer = a.encoder.EncodeArrayElem(a.buffer, value)
+	} else {
+		a.buffer = a.encoder.EncodeInt(a.buffer, a.nextKey(), value)
+	}
+	return a
+}
+
+// Int64 appends an element typed int64 and returns the array emitter itself.
+func (a *ArrayEmitter) Int64(value int64) *ArrayEmitter {
+	if a.encoder.nested {
+		a.buffer = a.encoder.EncodeArrayElem(a.buffer, value)
+	} else {
+		a.buffer = a.encoder.EncodeInt64(a.buffer, a.nextKey(), value)
+	}
+	return a
+}
+
+// Uint64 appends an element typed uint64 and returns the array emitter itself.
+func (a *ArrayEmitter) Uint64(value uint64) *ArrayEmitter {
+	if a.encoder.nested {
+		a.buffer = a.encoder.EncodeArrayElem(a.buffer, value)
+	} else {
+		a.buffer = a.encoder.EncodeUint64(a.buffer, a.nextKey(), value)
+	}
+	return a
+}
+
+// Float64 appends an element typed float64 and returns the array emitter itself.
+func (a *ArrayEmitter) Float64(value float64) *ArrayEmitter {
+	if a.encoder.nested {
+		a.buffer = a.encoder.EncodeArrayElem(a.buffer, value)
+	} else {
+		a.buffer = a.encoder.EncodeFloat64(a.buffer, a.nextKey(), value)
+	}
+	return a
+}
+
+// Bool appends an element typed bool and returns the array emitter itself.
+func (a *ArrayEmitter) Bool(value bool) *ArrayEmitter {
+	if a.encoder.nested {
+		a.buffer = a.encoder.EncodeArrayElem(a.buffer, value)
+	} else {
+		a.buffer = a.encoder.EncodeBool(a.buffer, a.nextKey(), value)
+	}
+	return a
+}
+
+// Time appends an element typed time.Time and returns the array emitter itself.
+func (a *ArrayEmitter) Time(value time.Time) *ArrayEmitter {
+	if a.encoder.nested {
+		a.buffer = a.encoder.EncodeArrayElem(a.buffer, value)
+	} else {
+		a.buffer = a.encoder.EncodeTime(a.buffer, a.nextKey(), value)
+	}
+	return a
+}
+
+// Dict appends a nested object as the element, whose fields are appended
+// by the function f, and returns the array emitter itself.
+func (a *ArrayEmitter) Dict(f func(d *Emitter)) *ArrayEmitter {
+	if a.encoder.nested {
+		a.buffer = a.encoder.EncodeElemObjectBegin(a.buffer)
+		f((*Emitter)(a))
+		a.buffer = a.encoder.EncodeObjectEnd(a.buffer)
+	} else {
+		prefix, index := a.prefix, a.index
+		a.prefix = a.nextKey() + "."
+		f((*Emitter)(a))
+		a.prefix, a.index = prefix, index+1
+	}
+	return a
+}
+
+// Array appends a nested array as the element, whose elements are appended
+// by the function f, and returns the array emitter itself.
+func (a *ArrayEmitter) Array(f func(a *ArrayEmitter)) *ArrayEmitter {
+	if a.encoder.nested {
+		a.buffer = a.encoder.EncodeElemArrayBegin(a.buffer)
+		f(a)
+		a.buffer = a.encoder.EncodeArrayEnd(a.buffer)
+	} else {
+		prefix, index := a.prefix, a.index
+		a.prefix, a.index = a.nextKey()+".", 0
+		f(a)
+		a.prefix, a.index = prefix, index+1
+	}
+	return a
+}
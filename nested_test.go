@@ -0,0 +1,97 @@
+// Copyright 2026 xgfone
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package log
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/xgfone/go-log/encoder"
+)
+
+// flatEncoder hides the optional interfaces of the wrapped encoder.
+type flatEncoder struct{ Encoder }
+
+func emitNested(logger Logger) {
+	logger.Info().
+		Str("k1", "v1").
+		Dict("k2", func(d *Emitter) {
+			d.Int("k21", 21).Dict("k22", func(d *Emitter) {
+				d.Kv("k221", "v221")
+			})
+		}).
+		Array("k3", func(a *ArrayEmitter) {
+			a.Str("v31").Int(32).Dict(func(d *Emitter) {
+				d.Bool("k331", true)
+			}).Array(func(a *ArrayEmitter) {
+				a.Float64(3.41).Any(nil)
+			}).Uint64(35)
+		}).
+		Dict("k4", func(*Emitter) {}).
+		Array("k5", func(*ArrayEmitter) {}).
+		Print("msg")
+}
+
+func TestEmitterNested(t *testing.T) {
+	buf := bytes.NewBuffer(nil)
+	logger := New("").WithWriter(buf).WithEncoder(newTestEncoder())
+	emitNested(logger)
+
+	expects := []string{
+		`{"lvl":"info","k1":"v1","k2":{"k21":21,"k22":{"k221":"v221"}},` +
+			`"k3":["v31",32,{"k331":true},[3.41,null],35],"k4":{},"k5":[],"msg":"msg"}`,
+		``,
+	}
+	testStrings(t, "nested", expects, strings.Split(buf.String(), "\n"))
+
+	buf.Reset()
+	logger = logger.WithEncoder(flatEncoder{newTestEncoder()})
+	emitNested(logger)
+
+	expects = []string{
+		`{"lvl":"info","k1":"v1","k2.k21":21,"k2.k22.k221":"v221",` +
+			`"k3.0":"v31","k3.1":32,"k3.2.k331":true,"k3.3.0":3.41,"k3.3.1":null,"k3.4":35,"msg":"msg"}`,
+		``,
+	}
+	testStrings(t, "flatten", expects, strings.Split(buf.String(), "\n"))
+}
+
+type testObject map[string]int
+
+func (o testObject) MarshalLogObject(enc encoder.FieldEncoder) {
+	enc.AddInt64("a", int64(o["a"]))
+	enc.AddInt64("b", int64(o["b"]))
+}
+
+func TestEmitterMarshaler(t *testing.T) {
+	buf := bytes.NewBuffer(nil)
+	logger := New("").WithWriter(buf).WithEncoder(newTestEncoder())
+	logger.Info().Kv("k1", testObject{"a": 1, "b": 2}).Array("k2", func(a *ArrayEmitter) {
+		a.Any(testObject{"a": 3})
+	}).Print("msg")
+
+	logger = logger.WithEncoder(flatEncoder{newTestEncoder()}).WithContext("k0", testObject{})
+	logger.Info().Kv("k1", testObject{"a": 1, "b": 2}).Array("k2", func(a *ArrayEmitter) {
+		a.Any(testObject{"a": 3})
+	}).Print("msg")
+
+	expects := []string{
+		`{"lvl":"info","k1":{"a":1,"b":2},"k2":[{"a":3,"b":0}],"msg":"msg"}`,
+		`{"lvl":"info","k0.a":0,"k0.b":0,"k1.a":1,"k1.b":2,"k2.0.a":3,"k2.0.b":0,"msg":"msg"}`,
+		``,
+	}
+	testStrings(t, "marshaler", expects, strings.Split(buf.String(), "\n"))
+}
@@ -81,14 +81,25 @@ func (o *Output) SetEncoder(enc Encoder) {
 }
 
 // WithEncoder returns a new logger with the new output created the new encoder
-// and the original writer, which will also re-encode all the key-value contexts.
+// and the original writer, which will also re-encode all the key-value contexts
+// and groups.
 func (l Logger) WithEncoder(encoder Encoder) Logger {
 	l.Output = l.Output.clone()
 	l.Output.SetEncoder(encoder)
 
-	ctxs := l.ctxs
-	l.ctx, l.ctxs = nil, nil
-	return l.WithContexts(ctxs...)
+	ctxs, groups := l.ctxs, l.groups
+	l = l.Clone()
+	l.ctx, l.ctxs, l.groups = nil, nil, nil
+	l.prefix, l.gctx, l.gclose = "", 0, 0
+
+	var start int
+	for _, group := range groups {
+		l.appendContexts(ctxs[start:group.index]...)
+		l.openGroup(group.name)
+		start = group.index
+	}
+	l.appendContexts(ctxs[start:]...)
+	return l
 }
 
 // WithWriter returns a new logger with the writer.
@@ -0,0 +1,300 @@
+// Copyright 2026 xgfone
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build go1.21
+// +build go1.21
+
+package slog
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math"
+	"runtime"
+	"time"
+
+	"github.com/xgfone/go-log"
+	"github.com/xgfone/go-log/encoder"
+)
+
+var (
+	_ encoder.Int64Encoder       = Encoder{}
+	_ encoder.Uint64Encoder      = Encoder{}
+	_ encoder.Float64Encoder     = Encoder{}
+	_ encoder.BoolEncoder        = Encoder{}
+	_ encoder.StringEncoder      = Encoder{}
+	_ encoder.TimeEncoder        = Encoder{}
+	_ encoder.DurationEncoder    = Encoder{}
+	_ encoder.StringSliceEncoder = Encoder{}
+	_ encoder.ObjectEncoder      = Encoder{}
+	_ encoder.ArrayEncoder       = Encoder{}
+)
+
+// The kinds of the items in the encoded log record.
+const (
+	kindRecord byte = iota + 1 // name, time
+	kindMsg                    // msg
+	kindPC                     // pc
+	kindNil
+	kindString
+	kindInt64
+	kindUint64
+	kindFloat64
+	kindBool
+	kindTime
+	kindDuration
+	kindStrings
+	kindAny // The value formatted by fmt.Sprint
+	kindObject
+	kindObjectEnd
+	kindArray
+	kindArrayEnd
+)
+
+// sourcePC is the program counter of the caller added by the hook Source.
+type sourcePC uintptr
+
+// Source returns a hook to add the program counter of the caller
+// into the log record, which is used as the PC of the slog record by Writer.
+//
+// Notice: it only works with Encoder.
+func Source() log.Hook {
+	return log.HookFunc(func(e *log.Emitter, name string, level, depth int) {
+		if pc := e.PC(); pc != 0 {
+			e.Kv("", sourcePC(pc))
+			return
+		}
+
+		var pcs [1]uintptr
+		if runtime.Callers(depth+2, pcs[:]) > 0 { // Skip Callers and this hook.
+			e.Kv("", sourcePC(pcs[0]))
+		}
+	})
+}
+
+// Encoder is a log encoder to encode the log record in the binary format,
+// which keeps the types of the key-values, such as int64 and time.Time,
+// so that Writer builds the slog record from it directly.
+//
+// The nested object is encoded as the group, and the nested array is encoded
+// as []interface{}. The value of the other types, such as map and error,
+// is formatted as the string by fmt.Sprint.
+type Encoder struct{}
+
+// NewEncoder returns a new Encoder.
+func NewEncoder() Encoder { return Encoder{} }
+
+// Start implements the interface log.Encoder.
+func (enc Encoder) Start(dst []byte, name, level string) []byte {
+	dst = append(dst, kindRecord)
+	dst = appendString(dst, name)
+	return binary.AppendVarint(dst, encoder.Now().UnixNano())
+}
+
+// End implements the interface log.Encoder.
+func (enc Encoder) End(dst []byte, msg string) []byte {
+	return appendString(append(dst, kindMsg), msg)
+}
+
+// Encode implements the interface log.Encoder.
+func (enc Encoder) Encode(dst []byte, key string, value interface{}) []byte {
+	if pc, ok := value.(sourcePC); ok {
+		return binary.AppendUvarint(append(dst, kindPC), uint64(pc))
+	}
+	return enc.encode(dst, key, false, value)
+}
+
+// encode encodes the value with the key, or as the array element if elem is true.
+func (enc Encoder) encode(dst []byte, key string, elem bool, value interface{}) []byte {
+	switch v := value.(type) {
+	case nil:
+		return appendHead(dst, kindNil, key, elem)
+	case int:
+		return appendInt64(dst, key, elem, int64(v))
+	case int8:
+		return appendInt64(dst, key, elem, int64(v))
+	case int16:
+		return appendInt64(dst, key, elem, int64(v))
+	case int32:
+		return appendInt64(dst, key, elem, int64(v))
+	case int64:
+		return appendInt64(dst, key, elem, v)
+	case uint:
+		return appendUint64(dst, key, elem, uint64(v))
+	case uint8:
+		return appendUint64(dst, key, elem, uint64(v))
+	case uint16:
+		return appendUint64(dst, key, elem, uint64(v))
+	case uint32:
+		return appendUint64(dst, key, elem, uint64(v))
+	case uint64:
+		return appendUint64(dst, key, elem, v)
+	case float32:
+		return appendFloat64(dst, key, elem, float64(v))
+	case float64:
+		return appendFloat64(dst, key, elem, v)
+	case bool:
+		return appendBool(dst, key, elem, v)
+	case string:
+		return appendString(appendHead(dst, kindString, key, elem), v)
+	case []string:
+		return appendStrings(dst, key, elem, v)
+	case time.Time:
+		return appendTime(dst, key, elem, v)
+	case time.Duration:
+		return binary.AppendVarint(appendHead(dst, kindDuration, key, elem), int64(v))
+	case encoder.ObjectMarshaler:
+		if elem {
+			dst, _ = encoder.EncodeElem(dst, enc, v)
+			return dst
+		}
+		return encoder.EncodeObject(dst, enc, key, v)
+	case encoder.ArrayMarshaler:
+		if elem {
+			dst, _ = encoder.EncodeElem(dst, enc, v)
+			return dst
+		}
+		return encoder.EncodeArray(dst, enc, key, v)
+	default:
+		return appendString(appendHead(dst, kindAny, key, elem), fmt.Sprint(v))
+	}
+}
+
+// EncodeInt64 implements the interface encoder.Int64Encoder.
+func (enc Encoder) EncodeInt64(dst []byte, key string, value int64) []byte {
+	return appendInt64(dst, key, false, value)
+}
+
+// EncodeUint64 implements the interface encoder.Uint64Encoder.
+func (enc Encoder) EncodeUint64(dst []byte, key string, value uint64) []byte {
+	return appendUint64(dst, key, false, value)
+}
+
+// EncodeFloat64 implements the interface encoder.Float64Encoder.
+func (enc Encoder) EncodeFloat64(dst []byte, key string, value float64) []byte {
+	return appendFloat64(dst, key, false, value)
+}
+
+// EncodeBool implements the interface encoder.BoolEncoder.
+func (enc Encoder) EncodeBool(dst []byte, key string, value bool) []byte {
+	return appendBool(dst, key, false, value)
+}
+
+// EncodeString implements the interface encoder.StringEncoder.
+func (enc Encoder) EncodeString(dst []byte, key string, value string) []byte {
+	return appendString(appendHead(dst, kindString, key, false), value)
+}
+
+// EncodeTime implements the interface encoder.TimeEncoder.
+func (enc Encoder) EncodeTime(dst []byte, key string, value time.Time) []byte {
+	return appendTime(dst, key, false, value)
+}
+
+// EncodeDuration implements the interface encoder.DurationEncoder.
+func (enc Encoder) EncodeDuration(dst []byte, key string, value time.Duration) []byte {
+	return binary.AppendVarint(appendHead(dst, kindDuration, key, false), int64(value))
+}
+
+// EncodeStringSlice implements the interface encoder.StringSliceEncoder.
+func (enc Encoder) EncodeStringSlice(dst []byte, key string, value []string) []byte {
+	return appendStrings(dst, key, false, value)
+}
+
+// EncodeObjectBegin implements the interface encoder.ObjectEncoder.
+func (enc Encoder) EncodeObjectBegin(dst []byte, key string) []byte {
+	return appendHead(dst, kindObject, key, false)
+}
+
+// EncodeObjectEnd implements the interface encoder.ObjectEncoder.
+func (enc Encoder) EncodeObjectEnd(dst []byte) []byte {
+	return append(dst, kindObjectEnd)
+}
+
+// EncodeArrayBegin implements the interface encoder.ArrayEncoder.
+func (enc Encoder) EncodeArrayBegin(dst []byte, key string) []byte {
+	return appendHead(dst, kindArray, key, false)
+}
+
+// EncodeArrayEnd implements the interface encoder.ArrayEncoder.
+func (enc Encoder) EncodeArrayEnd(dst []byte) []byte {
+	return append(dst, kindArrayEnd)
+}
+
+// EncodeArrayElem implements the interface encoder.ArrayEncoder.
+func (enc Encoder) EncodeArrayElem(dst []byte, value interface{}) []byte {
+	return enc.encode(dst, "", true, value)
+}
+
+// EncodeElemObjectBegin implements the interface encoder.ArrayEncoder.
+func (enc Encoder) EncodeElemObjectBegin(dst []byte) []byte {
+	return append(dst, kindObject)
+}
+
+// EncodeElemArrayBegin implements the interface encoder.ArrayEncoder.
+func (enc Encoder) EncodeElemArrayBegin(dst []byte) []byte {
+	return append(dst, kindArray)
+}
+
+/// ----------------------------------------------------------------------- ///
+
+// appendHead appends the kind and the key, which is omitted for the element.
+func appendHead(dst []byte, kind byte, key string, elem bool) []byte {
+	dst = append(dst, kind)
+	if !elem {
+		dst = appendString(dst, key)
+	}
+	return dst
+}
+
+func appendString(dst []byte, s string) []byte {
+	dst = binary.AppendUvarint(dst, uint64(len(s)))
+	return append(dst, s...)
+}
+
+func appendInt64(dst []byte, key string, elem bool, v int64) []byte {
+	return binary.AppendVarint(appendHead(dst, kindInt64, key, elem), v)
+}
+
+func appendUint64(dst []byte, key string, elem bool, v uint64) []byte {
+	return binary.AppendUvarint(appendHead(dst, kindUint64, key, elem), v)
+}
+
+func appendFloat64(dst []byte, key string, elem bool, v float64) []byte {
+	dst = appendHead(dst, kindFloat64, key, elem)
+	return binary.LittleEndian.AppendUint64(dst, math.Float64bits(v))
+}
+
+func appendBool(dst []byte, key string, elem bool, v bool) []byte {
+	dst = appendHead(dst, kindBool, key, elem)
+	if v {
+		return append(dst, 1)
+	}
+	return append(dst, 0)
+}
+
+func appendTime(dst []byte, key string, elem bool, v time.Time) []byte {
+	data, err := v.MarshalBinary()
+	if err != nil { // The offset of the time zone is not the whole minutes.
+		data, _ = v.UTC().MarshalBinary()
+	}
+	return appendString(appendHead(dst, kindTime, key, elem), string(data))
+}
+
+func appendStrings(dst []byte, key string, elem bool, v []string) []byte {
+	dst = binary.AppendUvarint(appendHead(dst, kindStrings, key, elem), uint64(len(v)))
+	for _, s := range v {
+		dst = appendString(dst, s)
+	}
+	return dst
+}
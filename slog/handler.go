@@ -0,0 +1,256 @@
+// Copyright 2026 xgfone
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build go1.21
+// +build go1.21
+
+// Package slog provides the adapters between the stdlib log/slog
+// and the key-value logger.
+package slog
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/xgfone/go-log"
+	"github.com/xgfone/go-log/encoder"
+)
+
+var _ slog.Handler = &Handler{}
+
+// LevelFromSlog converts the slog level to the level of the key-value logger.
+//
+// slog.LevelDebug, slog.LevelInfo, slog.LevelWarn and slog.LevelError are
+// converted to log.LvlDebug, log.LvlInfo, log.LvlWarn and log.LvlError.
+// Each step of the slog level between them is equal to 5 steps of the logger
+// level, and the result is limited in [log.LvlTrace, log.LvlPanic), because
+// the slog level is only a number, and the record with it must never panic
+// or exit the program like log.LvlPanic and log.LvlFatal.
+func LevelFromSlog(level slog.Level) int {
+	lvl := log.LvlInfo + int(level)*5
+	switch {
+	case lvl < log.LvlTrace:
+		return log.LvlTrace
+	case lvl >= log.LvlPanic:
+		return log.LvlPanic - 1
+	default:
+		return lvl
+	}
+}
+
+// LevelToSlog is the inverse of LevelFromSlog, which converts the level
+// of the key-value logger to the slog level.
+//
+// The offset from log.LvlInfo is divided by 5 with the floor, so the levels
+// below log.LvlInfo, such as log.LvlInfo-1, are converted to the slog levels
+// below slog.LevelInfo.
+func LevelToSlog(level int) slog.Level {
+	offset := level - log.LvlInfo
+	if offset < 0 {
+		offset -= 4
+	}
+	return slog.Level(offset / 5)
+}
+
+// Handler is a slog handler to emit the log record by log.Logger.
+type Handler struct {
+	logger log.Logger
+	groups []string // The groups not opened by the logger.
+}
+
+// NewHandler returns a new slog handler based on the logger.
+//
+// Notice: the stack depth of the logger is ignored, because the caller
+// is calculated from the program counter of the slog record.
+func NewHandler(logger log.Logger) *Handler {
+	return &Handler{logger: logger.WithDepth(0)}
+}
+
+// Logger returns the inner logger.
+func (h *Handler) Logger() log.Logger { return h.logger }
+
+// Enabled implements the interface slog.Handler.
+func (h *Handler) Enabled(_ context.Context, level slog.Level) bool {
+	return h.logger.Enabled(LevelFromSlog(level))
+}
+
+// WithAttrs implements the interface slog.Handler.
+//
+// The attributes are pre-encoded into the logger contexts, and the groups
+// before them are opened by the logger.
+func (h *Handler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	kvs := make([]interface{}, 0, len(attrs)*2)
+	for _, attr := range attrs {
+		kvs = appendAttr(kvs, attr)
+	}
+
+	if len(kvs) == 0 {
+		return h
+	}
+
+	logger := h.logger
+	for _, group := range h.groups {
+		logger = logger.WithGroup(group)
+	}
+	return &Handler{logger: logger.WithContexts(kvs...)}
+}
+
+// WithGroup implements the interface slog.Handler.
+//
+// The group is opened by the logger until any attribute is added into it,
+// so the empty group is ignored.
+func (h *Handler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+
+	groups := h.groups[:len(h.groups):len(h.groups)]
+	return &Handler{logger: h.logger, groups: append(groups, name)}
+}
+
+// Handle implements the interface slog.Handler, which also appends
+// the key-value contexts extracted from ctx by the registered
+// context extractors. See log.RegisterCtxExtractor.
+func (h *Handler) Handle(ctx context.Context, r slog.Record) error {
+	e := h.logger.LevelPC(LevelFromSlog(r.Level), r.PC).Ctx(ctx)
+	if e == nil {
+		return nil
+	}
+
+	if len(h.groups) == 0 {
+		r.Attrs(func(attr slog.Attr) bool {
+			addAttr(e, attr)
+			return true
+		})
+	} else if r.NumAttrs() > 0 {
+		h.addGroup(e, 0, r)
+	}
+
+	e.Printf(r.Message)
+	return nil
+}
+
+// addGroup appends the i-th group not opened by the logger as the nested
+// object, which contains the groups after it and the attributes of the record.
+func (h *Handler) addGroup(e *log.Emitter, i int, r slog.Record) {
+	e.Dict(h.groups[i], func(d *log.Emitter) {
+		if next := i + 1; next < len(h.groups) {
+			h.addGroup(d, next, r)
+		} else {
+			r.Attrs(func(attr slog.Attr) bool {
+				addAttr(d, attr)
+				return true
+			})
+		}
+	})
+}
+
+// addAttr appends the attribute into the emitter by following the rules
+// of slog.Handler, and the group is appended as the nested object.
+func addAttr(e *log.Emitter, attr slog.Attr) {
+	attr.Value = attr.Value.Resolve()
+	if attr.Equal(slog.Attr{}) {
+		return
+	}
+
+	if attr.Value.Kind() != slog.KindGroup {
+		e.Kv(attr.Key, attrValue(attr.Value))
+		return
+	}
+
+	group := attr.Value.Group()
+	if len(group) == 0 {
+		return
+	}
+
+	if attr.Key == "" {
+		for _, a := range group {
+			addAttr(e, a)
+		}
+		return
+	}
+
+	e.Dict(attr.Key, func(d *log.Emitter) {
+		for _, a := range group {
+			addAttr(d, a)
+		}
+	})
+}
+
+// appendAttr appends the attribute as the key-value pairs into kvs
+// by following the rules of slog.Handler, which is used to pre-encode
+// the attributes into the logger contexts.
+func appendAttr(kvs []interface{}, attr slog.Attr) []interface{} {
+	attr.Value = attr.Value.Resolve()
+	if attr.Equal(slog.Attr{}) {
+		return kvs
+	}
+
+	if attr.Value.Kind() != slog.KindGroup {
+		return append(kvs, attr.Key, attrValue(attr.Value))
+	}
+
+	group := attr.Value.Group()
+	if len(group) == 0 {
+		return kvs
+	}
+
+	if attr.Key == "" {
+		for _, a := range group {
+			kvs = appendAttr(kvs, a)
+		}
+		return kvs
+	}
+
+	subs := make([]interface{}, 0, len(group)*2)
+	for _, a := range group {
+		subs = appendAttr(subs, a)
+	}
+
+	if len(subs) == 0 {
+		return kvs
+	}
+	return append(kvs, attr.Key, attrsObject(subs))
+}
+
+// attrsObject is the key-value pairs of the group attribute,
+// which is encoded as the nested object in order.
+type attrsObject []interface{}
+
+func (o attrsObject) MarshalLogObject(enc encoder.FieldEncoder) {
+	for i, _len := 0, len(o); i < _len; i += 2 {
+		enc.AddAny(o[i].(string), o[i+1])
+	}
+}
+
+func attrValue(v slog.Value) interface{} {
+	switch v.Kind() {
+	case slog.KindString:
+		return v.String()
+	case slog.KindInt64:
+		return v.Int64()
+	case slog.KindUint64:
+		return v.Uint64()
+	case slog.KindFloat64:
+		return v.Float64()
+	case slog.KindBool:
+		return v.Bool()
+	case slog.KindDuration:
+		return v.Duration()
+	case slog.KindTime:
+		return v.Time()
+	default:
+		return v.Any()
+	}
+}
@@ -0,0 +1,202 @@
+// Copyright 2026 xgfone
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build go1.21
+// +build go1.21
+
+package slog
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"log/slog"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/xgfone/go-log"
+	"github.com/xgfone/go-log/encoder"
+)
+
+func testStrings(t *testing.T, prefix string, expects, results []string) {
+	if len(expects) != len(results) {
+		t.Errorf("%s: expect %d lines, but got %d: %v",
+			prefix, len(expects), len(results), results)
+		return
+	}
+
+	for i, line := range expects {
+		if results[i] != line {
+			t.Errorf("%s: %d line: expect '%s', but got '%s'",
+				prefix, i, line, results[i])
+		}
+	}
+}
+
+func TestLevel(t *testing.T) {
+	levels := map[slog.Level]int{
+		slog.LevelDebug - 8:  log.LvlTrace,
+		slog.LevelDebug - 4:  log.LvlTrace,
+		slog.LevelDebug:      log.LvlDebug,
+		slog.LevelInfo:       log.LvlInfo,
+		slog.LevelInfo + 1:   log.LvlInfo + 5,
+		slog.LevelWarn:       log.LvlWarn,
+		slog.LevelError:      log.LvlError,
+		slog.LevelError + 4:  log.LvlAlert,
+		slog.LevelError + 10: log.LvlPanic - 1,
+	}
+
+	for slevel, level := range levels {
+		if lvl := LevelFromSlog(slevel); lvl != level {
+			t.Errorf("slog level %d: expect %d, but got %d", slevel, level, lvl)
+		}
+	}
+
+	for _, slevel := range []slog.Level{slog.LevelDebug, slog.LevelInfo, slog.LevelWarn, slog.LevelError} {
+		if lvl := LevelToSlog(LevelFromSlog(slevel)); lvl != slevel {
+			t.Errorf("expect slog level %d, but got %d", slevel, lvl)
+		}
+	}
+}
+
+func TestHandler(t *testing.T) {
+	enc := encoder.NewJSONEncoder()
+	enc.TimeKey = ""
+
+	buf := bytes.NewBuffer(nil)
+	logger := log.New("test").WithWriter(buf).WithEncoder(enc).
+		WithHooks(log.Caller("caller")).WithLevel(log.LvlInfo)
+
+	slogger := slog.New(NewHandler(logger))
+	slogger.Debug("msg1")
+	slogger.Info("msg2", "k1", "v1", "k2", 2)
+	slogger.With("k3", "v3").Warn("msg3", slog.Group("g1", "k4", "v4"))
+	slogger.WithGroup("g2").With("k5", "v5").Error("msg4")
+	slogger.WithGroup("g3").Info("msg5")
+	slogger.WithGroup("g4").Info("msg7", "k7", "v7", slog.Group("g5", "k8", "v8", "k9", "v9"))
+	slogger.Log(context.Background(), slog.LevelInfo+1, "msg6", slog.Group("", "k6", "v6"))
+	slogger.WithGroup("g6").With(slog.Group("g7", "k10", 10, "k11", 11)).WithGroup("g8").Info("msg8", "k12", 12)
+
+	const prefix = `"logger":"test","caller":"handler_test.go:TestHandler:`
+	expects := []string{
+		`{"lvl":"info",` + prefix + `84","k1":"v1","k2":2,"msg":"msg2"}`,
+		`{"lvl":"warn","logger":"test","k3":"v3","caller":"handler_test.go:TestHandler:85","g1":{"k4":"v4"},"msg":"msg3"}`,
+		`{"lvl":"error",` + prefix + `86","g2":{"k5":"v5"},"msg":"msg4"}`,
+		`{"lvl":"info",` + prefix + `87","msg":"msg5"}`,
+		`{"lvl":"info",` + prefix + `88","g4":{"k7":"v7","g5":{"k8":"v8","k9":"v9"}},"msg":"msg7"}`,
+		`{"lvl":"info5",` + prefix + `89","k6":"v6","msg":"msg6"}`,
+		`{"lvl":"info","logger":"test","caller":"handler_test.go:TestHandler:90","g6":{"g7":{"k10":10,"k11":11},"g8":{"k12":12}},"msg":"msg8"}`,
+		``,
+	}
+	testStrings(t, "handler", expects, strings.Split(buf.String(), "\n"))
+}
+
+func TestHandlerHighLevel(t *testing.T) {
+	enc := encoder.NewJSONEncoder()
+	enc.TimeKey = ""
+
+	buf := bytes.NewBuffer(nil)
+	logger := log.New("").WithWriter(buf).WithEncoder(enc)
+
+	// Neither panic nor exit the program.
+	slogger := slog.New(NewHandler(logger))
+	slogger.Log(context.Background(), slog.LevelError+8, "msg1")
+	slogger.Log(context.Background(), slog.LevelError+100, "msg2")
+
+	expects := []string{
+		`{"lvl":"alert19","msg":"msg1"}`,
+		`{"lvl":"alert19","msg":"msg2"}`,
+		``,
+	}
+	testStrings(t, "high level", expects, strings.Split(buf.String(), "\n"))
+}
+
+func TestLevelToSlog(t *testing.T) {
+	levels := map[int]slog.Level{
+		log.LvlTrace:     slog.LevelDebug - 4,
+		log.LvlDebug - 1: slog.LevelDebug - 1,
+		log.LvlDebug:     slog.LevelDebug,
+		log.LvlInfo - 5:  slog.LevelInfo - 1,
+		log.LvlInfo - 4:  slog.LevelInfo - 1,
+		log.LvlInfo - 1:  slog.LevelInfo - 1,
+		log.LvlInfo:      slog.LevelInfo,
+		log.LvlInfo + 4:  slog.LevelInfo,
+		log.LvlWarn - 1:  slog.LevelWarn - 1,
+		log.LvlWarn:      slog.LevelWarn,
+		log.LvlError - 1: slog.LevelError - 1,
+		log.LvlError:     slog.LevelError,
+	}
+
+	for level, slevel := range levels {
+		if lvl := LevelToSlog(level); lvl != slevel {
+			t.Errorf("level %d: expect slog level %d, but got %d", level, slevel, lvl)
+		}
+	}
+}
+
+// deepHandler wraps the handler and calls it in a deep call stack.
+type deepHandler struct{ slog.Handler }
+
+func (h deepHandler) Handle(ctx context.Context, r slog.Record) error {
+	return h.handle(ctx, r, 100)
+}
+
+func (h deepHandler) handle(ctx context.Context, r slog.Record, depth int) error {
+	if depth > 0 {
+		return h.handle(ctx, r, depth-1)
+	}
+	return h.Handler.Handle(ctx, r)
+}
+
+func TestHandlerCaller(t *testing.T) {
+	enc := encoder.NewJSONEncoder()
+	enc.TimeKey = ""
+
+	buf := bytes.NewBuffer(nil)
+	logger := log.New("").WithWriter(buf).WithEncoder(enc).WithHooks(log.Caller("caller"))
+
+	_, _, line, _ := runtime.Caller(0)
+	slog.New(deepHandler{NewHandler(logger)}).Info("msg")
+
+	expect := fmt.Sprintf(`{"lvl":"info","caller":"handler_test.go:TestHandlerCaller:%d","msg":"msg"}`+"\n", line+1)
+	if s := buf.String(); s != expect {
+		t.Errorf("expect '%s', but got '%s'", expect, s)
+	}
+}
+
+type testCtxKey string
+
+func TestHandlerCtx(t *testing.T) {
+	log.RegisterCtxExtractor(log.CtxValueExtractor("rid", testCtxKey("rid")))
+
+	enc := encoder.NewJSONEncoder()
+	enc.TimeKey = ""
+
+	buf := bytes.NewBuffer(nil)
+	slogger := slog.New(NewHandler(log.New("").WithWriter(buf).WithEncoder(enc)))
+
+	ctx := context.WithValue(context.Background(), testCtxKey("rid"), "123")
+	slogger.InfoContext(ctx, "msg1", "k1", "v1")
+	slogger.WithGroup("g1").InfoContext(ctx, "msg2", "k2", "v2")
+	slogger.Info("msg3")
+
+	expects := []string{
+		`{"lvl":"info","rid":"123","k1":"v1","msg":"msg1"}`,
+		`{"lvl":"info","rid":"123","g1":{"k2":"v2"},"msg":"msg2"}`,
+		`{"lvl":"info","msg":"msg3"}`,
+		``,
+	}
+	testStrings(t, "ctx", expects, strings.Split(buf.String(), "\n"))
+}
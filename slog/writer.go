@@ -0,0 +1,238 @@
+// Copyright 2026 xgfone
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build go1.21
+// +build go1.21
+
+package slog
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"log/slog"
+	"math"
+	"time"
+
+	"github.com/xgfone/go-log"
+)
+
+// NewLogger returns a new logger with the name, which emits the log records
+// through the slog handler.
+//
+// The log records are encoded by Encoder, and forwarded to the handler
+// by Writer. The handler decides whether a level is enabled. And the source
+// of the record is added by the hook Source, so keep it if resetting
+// the hooks of the returned logger.
+func NewLogger(name string, handler slog.Handler) log.Logger {
+	enabled := func(_ string, level int) bool {
+		return handler.Enabled(context.Background(), LevelToSlog(level))
+	}
+
+	return log.New(name).
+		WithLevel(log.LvlTrace).
+		WithWriter(NewWriter(handler)).
+		WithEncoder(NewEncoder()).
+		WithHooks(Source()).
+		WithSampler(log.SamplerFunc(enabled))
+}
+
+// errInvalidRecord is returned when the log record is not encoded by Encoder.
+var errInvalidRecord = errors.New("the log record is not encoded by slog.Encoder")
+
+// Writer is a log writer to build the slog record from the log record
+// encoded by Encoder and forward it to the slog handler.
+//
+// The key-values of the log record are converted to the slog attributes
+// in order with their types, and the nested objects are converted to
+// the slog groups. The PC of the slog record is set if the logger has
+// the hook Source.
+type Writer struct {
+	Handler slog.Handler
+
+	// LoggerKey is the key of the attribute of the logger name if not empty.
+	//
+	// Default: "logger"
+	LoggerKey string
+}
+
+// NewWriter returns a new Writer with the slog handler.
+func NewWriter(handler slog.Handler) *Writer {
+	if handler == nil {
+		panic("slog.Writer: the handler is nil")
+	}
+	return &Writer{Handler: handler, LoggerKey: "logger"}
+}
+
+// Write implements the interface io.Writer, which is equal to
+// w.WriteLevel(log.LvlInfo, p).
+func (w *Writer) Write(p []byte) (n int, err error) {
+	return w.WriteLevel(log.LvlInfo, p)
+}
+
+// WriteLevel implements the interface writer.LevelWriter.
+func (w *Writer) WriteLevel(level int, p []byte) (n int, err error) {
+	d := decoder{data: p}
+	if d.byte() != kindRecord {
+		return 0, errInvalidRecord
+	}
+
+	name := d.string()
+	now := time.Unix(0, d.varint())
+
+	var attrs []slog.Attr
+	if name != "" && w.LoggerKey != "" {
+		attrs = append(attrs, slog.String(w.LoggerKey, name))
+	}
+
+	attrs = d.attrs(attrs, kindMsg)
+	msg := d.string()
+	if d.err != nil {
+		return 0, d.err
+	}
+
+	r := slog.NewRecord(now, LevelToSlog(level), msg, d.pc)
+	r.AddAttrs(attrs...)
+	if err = w.Handler.Handle(context.Background(), r); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+/// ----------------------------------------------------------------------- ///
+
+// decoder decodes the log record encoded by Encoder.
+type decoder struct {
+	data []byte
+	pc   uintptr
+	err  error
+}
+
+func (d *decoder) fail() {
+	if d.err == nil {
+		d.err = errInvalidRecord
+	}
+	d.data = nil
+}
+
+func (d *decoder) byte() (b byte) {
+	if len(d.data) == 0 {
+		d.fail()
+		return
+	}
+	b, d.data = d.data[0], d.data[1:]
+	return
+}
+
+func (d *decoder) uvarint() uint64 {
+	v, n := binary.Uvarint(d.data)
+	if n <= 0 {
+		d.fail()
+		return 0
+	}
+	d.data = d.data[n:]
+	return v
+}
+
+func (d *decoder) varint() int64 {
+	v, n := binary.Varint(d.data)
+	if n <= 0 {
+		d.fail()
+		return 0
+	}
+	d.data = d.data[n:]
+	return v
+}
+
+func (d *decoder) string() (s string) {
+	n := d.uvarint()
+	if n > uint64(len(d.data)) {
+		d.fail()
+		return
+	}
+	s, d.data = string(d.data[:n]), d.data[n:]
+	return
+}
+
+// attrs decodes the attributes into attrs until the kind end.
+func (d *decoder) attrs(attrs []slog.Attr, end byte) []slog.Attr {
+	for d.err == nil {
+		switch kind := d.byte(); kind {
+		case end:
+			return attrs
+		case kindPC:
+			d.pc = uintptr(d.uvarint())
+		default:
+			key := d.string()
+			attrs = append(attrs, slog.Attr{Key: key, Value: d.value(kind)})
+		}
+	}
+	return attrs
+}
+
+// value decodes the value of the kind.
+func (d *decoder) value(kind byte) slog.Value {
+	switch kind {
+	case kindNil:
+		return slog.AnyValue(nil)
+	case kindString:
+		return slog.StringValue(d.string())
+	case kindAny:
+		return slog.StringValue(d.string())
+	case kindInt64:
+		return slog.Int64Value(d.varint())
+	case kindUint64:
+		return slog.Uint64Value(d.uvarint())
+	case kindFloat64:
+		if len(d.data) < 8 {
+			d.fail()
+			return slog.Value{}
+		}
+		v := math.Float64frombits(binary.LittleEndian.Uint64(d.data))
+		d.data = d.data[8:]
+		return slog.Float64Value(v)
+	case kindBool:
+		return slog.BoolValue(d.byte() == 1)
+	case kindDuration:
+		return slog.DurationValue(time.Duration(d.varint()))
+	case kindTime:
+		var t time.Time
+		if err := t.UnmarshalBinary([]byte(d.string())); err != nil {
+			d.fail()
+		}
+		return slog.TimeValue(t)
+	case kindStrings:
+		n := d.uvarint()
+		if n > uint64(len(d.data)) {
+			d.fail()
+			return slog.Value{}
+		}
+		vs := make([]string, n)
+		for i := range vs {
+			vs[i] = d.string()
+		}
+		return slog.AnyValue(vs)
+	case kindObject:
+		return slog.GroupValue(d.attrs(nil, kindObjectEnd)...)
+	case kindArray:
+		var elems []interface{}
+		for kind := d.byte(); kind != kindArrayEnd && d.err == nil; kind = d.byte() {
+			elems = append(elems, d.value(kind).Any())
+		}
+		return slog.AnyValue(elems)
+	default:
+		d.fail()
+		return slog.Value{}
+	}
+}
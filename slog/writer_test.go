@@ -0,0 +1,144 @@
+// Copyright 2026 xgfone
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build go1.21
+// +build go1.21
+
+package slog
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"reflect"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/xgfone/go-log"
+	"github.com/xgfone/go-log/encoder"
+)
+
+func TestNewLogger(t *testing.T) {
+	buf := bytes.NewBuffer(nil)
+	handler := slog.NewTextHandler(buf, &slog.HandlerOptions{
+		Level: slog.LevelInfo,
+		ReplaceAttr: func(groups []string, a slog.Attr) slog.Attr {
+			if len(groups) == 0 && a.Key == slog.TimeKey {
+				return slog.Attr{}
+			}
+			return a
+		},
+	})
+
+	logger := NewLogger("test", handler).WithContext("k0", "v0")
+	logger.Debug().Print("msg1")
+	logger.Level(log.LvlInfo-1, 0).Print("msg0")
+	logger.Info().Kv("k1", "v1").Int("k2", 2).Print("msg2")
+	logger.Warn().Kv("k3", map[string]interface{}{"k4": 4.5}).Print("msg3")
+	logger.Error().Kv("k5", []int{5, 6}).Print("msg4")
+
+	expects := []string{
+		`level=INFO msg=msg2 logger=test k0=v0 k1=v1 k2=2`,
+		`level=WARN msg=msg3 logger=test k0=v0 k3=map[k4:4.5]`,
+		`level=ERROR msg=msg4 logger=test k0=v0 k5="[5 6]"`,
+		``,
+	}
+	testStrings(t, "logger", expects, strings.Split(buf.String(), "\n"))
+}
+
+// recordHandler is a slog handler to collect the records.
+type recordHandler struct{ records []slog.Record }
+
+func (h *recordHandler) Enabled(context.Context, slog.Level) bool { return true }
+func (h *recordHandler) WithAttrs([]slog.Attr) slog.Handler       { return h }
+func (h *recordHandler) WithGroup(string) slog.Handler            { return h }
+func (h *recordHandler) Handle(_ context.Context, r slog.Record) error {
+	h.records = append(h.records, r)
+	return nil
+}
+
+type testArray []int
+
+func (a testArray) MarshalLogArray(enc encoder.ElemEncoder) {
+	for _, v := range a {
+		enc.AppendAny(v)
+	}
+}
+
+func TestWriterRecord(t *testing.T) {
+	now := time.Date(2026, time.October, 16, 12, 0, 0, 0, time.UTC)
+
+	handler := new(recordHandler)
+	logger := NewLogger("", handler)
+	_, _, line, _ := runtime.Caller(0)
+	logger.Info().
+		Int("int", 1).
+		Uint64("uint", 2).
+		Float64("float", 3.5).
+		Bool("bool", true).
+		Kv("time", now).
+		Kv("duration", time.Second).
+		StrSlice("strs", []string{"a", "b"}).
+		Kv("nil", nil).
+		Dict("dict", func(d *log.Emitter) { d.Str("k", "v") }).
+		Kv("array", testArray{1, 2}).
+		Printf("msg")
+	line++
+
+	if len(handler.records) != 1 {
+		t.Fatalf("expect %d record, but got %d", 1, len(handler.records))
+	}
+
+	r := handler.records[0]
+	if r.Message != "msg" || r.Level != slog.LevelInfo {
+		t.Errorf("unexpected record: level=%s, msg=%s", r.Level, r.Message)
+	}
+
+	frame, _ := runtime.CallersFrames([]uintptr{r.PC}).Next()
+	if !strings.HasSuffix(frame.File, "writer_test.go") || frame.Line != line {
+		t.Errorf("expect the source 'writer_test.go:%d', but got '%s:%d'", line, frame.File, frame.Line)
+	}
+
+	attrs := make(map[string]interface{})
+	r.Attrs(func(attr slog.Attr) bool {
+		if attr.Value.Kind() == slog.KindGroup {
+			attrs[attr.Key] = attr.Value.Group()
+		} else {
+			attrs[attr.Key] = attr.Value.Any()
+		}
+		return true
+	})
+
+	expects := map[string]interface{}{
+		"int":      int64(1),
+		"uint":     uint64(2),
+		"float":    3.5,
+		"bool":     true,
+		"time":     now,
+		"duration": time.Second,
+		"strs":     []string{"a", "b"},
+		"nil":      nil,
+		"dict":     []slog.Attr{slog.String("k", "v")},
+		"array":    []interface{}{int64(1), int64(2)},
+	}
+	if !reflect.DeepEqual(attrs, expects) {
+		t.Errorf("expect attributes %v, but got %v", expects, attrs)
+	}
+
+	if _, err := NewWriter(handler).Write([]byte(`{"msg":"msg"}`)); err == nil {
+		t.Errorf("expect an error, but got nil")
+	}
+}
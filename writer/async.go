@@ -0,0 +1,304 @@
+// Copyright 2026 xgfone
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package writer
+
+import (
+	"errors"
+	"io"
+	"sync"
+	"sync/atomic"
+)
+
+// AsyncPolicy is the policy of AsyncLevelWriter when the queue is full.
+type AsyncPolicy int
+
+// Predefine some policies when the queue is full.
+const (
+	// AsyncBlock blocks the writing until the queue is not full.
+	AsyncBlock AsyncPolicy = iota
+
+	// AsyncDropNewest drops the record being written.
+	AsyncDropNewest
+
+	// AsyncDropOldest drops the oldest record in the queue.
+	AsyncDropOldest
+
+	// AsyncDropBelowLevel drops the record being written if its level
+	// is less than AsyncOptions.DropLevel, or blocks like AsyncBlock.
+	AsyncDropBelowLevel
+)
+
+// ErrAsyncClosed is returned when writing the data into the closed AsyncLevelWriter.
+var ErrAsyncClosed = errors.New("the async writer has been closed")
+
+// AsyncOptions is the options of AsyncLevelWriter.
+type AsyncOptions struct {
+	// QueueSize is the maximum number of the records in the queue.
+	//
+	// Default: 1024
+	QueueSize int
+
+	// Policy is the policy when the queue is full.
+	//
+	// Default: AsyncBlock
+	Policy AsyncPolicy
+
+	// DropLevel is the level below which the record is dropped
+	// when the queue is full, which is only used by AsyncDropBelowLevel.
+	//
+	// Default: 0
+	DropLevel int
+
+	// SyncLevel is the level from which the record is written synchronously,
+	// that's, the writing returns after the record and all the records
+	// before it have been written, so that the log is not lost before
+	// the program exits or panics.
+	//
+	// If 0, use the default. So set it to a negative value, such as -1,
+	// to write all the records synchronously.
+	//
+	// Default: LevelPanic (log.LvlPanic)
+	SyncLevel int
+
+	// OnError is called in the background goroutine if set
+	// when failing to write the record into the wrapped writer.
+	//
+	// Default: nil
+	OnError func(err error)
+}
+
+// AsyncLevelWriter is a writer to write the records into the wrapped writer
+// asynchronously by a bounded queue and a background goroutine, which
+// implements the interfaces LevelWriter, WrappedWriter, Flusher and io.Closer.
+//
+// It is thread-safe, and the wrapped writer is only used by
+// the background goroutine, so it is unnecessary to be thread-safe.
+type AsyncLevelWriter struct {
+	dropped uint64
+	failed  uint64
+
+	writer  LevelWriter
+	options AsyncOptions
+
+	lock     sync.Mutex
+	notEmpty *sync.Cond
+	notFull  *sync.Cond
+	queue    []asyncRecord
+	records  int // The number of the records in the queue, except flushes.
+	closed   bool
+	exit     chan struct{}
+}
+
+type asyncRecord struct {
+	level   int
+	leveled bool
+	data    *asyncBuffer
+	flush   chan error // Only for the flush request
+}
+
+type asyncBuffer struct{ buf []byte }
+
+var asyncBufPool = sync.Pool{New: func() interface{} {
+	return &asyncBuffer{buf: make([]byte, 0, 256)}
+}}
+
+// AsyncWriter returns a new AsyncLevelWriter to write the records
+// into writer asynchronously, and starts the background goroutine.
+func AsyncWriter(writer io.Writer, options AsyncOptions) *AsyncLevelWriter {
+	if writer == nil {
+		panic("AsyncWriter: the wrapped writer is nil")
+	}
+
+	if options.QueueSize <= 0 {
+		options.QueueSize = 1024
+	}
+	if options.SyncLevel == 0 {
+		options.SyncLevel = LevelPanic
+	}
+
+	w := &AsyncLevelWriter{
+		writer:  ToLevelWriter(writer),
+		options: options,
+		queue:   make([]asyncRecord, 0, options.QueueSize),
+		exit:    make(chan struct{}),
+	}
+	w.notEmpty = sync.NewCond(&w.lock)
+	w.notFull = sync.NewCond(&w.lock)
+	go w.loop()
+	return w
+}
+
+// UnwrapWriter implements the interface WrappedWriter.
+func (w *AsyncLevelWriter) UnwrapWriter() io.Writer { return w.writer }
+
+// Dropped returns the number of the records dropped by the policy.
+func (w *AsyncLevelWriter) Dropped() uint64 { return atomic.LoadUint64(&w.dropped) }
+
+// Failed returns the number of the records failed to be written.
+func (w *AsyncLevelWriter) Failed() uint64 { return atomic.LoadUint64(&w.failed) }
+
+// Len returns the number of the records in the queue.
+func (w *AsyncLevelWriter) Len() (n int) {
+	w.lock.Lock()
+	n = w.records
+	w.lock.Unlock()
+	return
+}
+
+// Write implements the interface io.Writer, which writes the data
+// into the wrapped writer by Write and is never dropped by the level.
+func (w *AsyncLevelWriter) Write(p []byte) (n int, err error) {
+	return w.write(asyncRecord{}, p)
+}
+
+// WriteLevel implements the interface LevelWriter.
+func (w *AsyncLevelWriter) WriteLevel(level int, p []byte) (n int, err error) {
+	if n, err = w.write(asyncRecord{level: level, leveled: true}, p); err == nil &&
+		level >= w.options.SyncLevel {
+		w.Flush()
+	}
+	return
+}
+
+func (w *AsyncLevelWriter) write(r asyncRecord, p []byte) (n int, err error) {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+
+	for !w.closed && w.records >= w.options.QueueSize {
+		switch w.options.Policy {
+		case AsyncDropNewest:
+			atomic.AddUint64(&w.dropped, 1)
+			return len(p), nil
+
+		case AsyncDropOldest:
+			w.dropOldest()
+
+		case AsyncDropBelowLevel:
+			if r.leveled && r.level < w.options.DropLevel {
+				atomic.AddUint64(&w.dropped, 1)
+				return len(p), nil
+			}
+			w.notFull.Wait()
+
+		default:
+			w.notFull.Wait()
+		}
+	}
+
+	if w.closed {
+		return 0, ErrAsyncClosed
+	}
+
+	r.data = asyncBufPool.Get().(*asyncBuffer)
+	r.data.buf = append(r.data.buf[:0], p...)
+	w.queue = append(w.queue, r)
+	w.records++
+	w.notEmpty.Signal()
+	return len(p), nil
+}
+
+// dropOldest drops the oldest record in the queue except the flush requests.
+func (w *AsyncLevelWriter) dropOldest() {
+	for i, r := range w.queue {
+		if r.flush == nil {
+			asyncBufPool.Put(r.data)
+			copy(w.queue[i:], w.queue[i+1:])
+			w.queue[len(w.queue)-1] = asyncRecord{}
+			w.queue = w.queue[:len(w.queue)-1]
+			w.records--
+			atomic.AddUint64(&w.dropped, 1)
+			return
+		}
+	}
+}
+
+// Flush waits until all the records written before have been written
+// into the wrapped writer, then flushes the wrapped writer.
+func (w *AsyncLevelWriter) Flush() error {
+	w.lock.Lock()
+	if w.closed {
+		w.lock.Unlock()
+		return nil
+	}
+
+	flush := make(chan error, 1)
+	w.queue = append(w.queue, asyncRecord{flush: flush})
+	w.notEmpty.Signal()
+	w.lock.Unlock()
+	return <-flush
+}
+
+// Close stops to accept the new records, waits until all the records
+// in the queue have been written, then closes the wrapped writer.
+func (w *AsyncLevelWriter) Close() error {
+	w.lock.Lock()
+	if w.closed {
+		w.lock.Unlock()
+		return nil
+	}
+
+	w.closed = true
+	w.notEmpty.Broadcast()
+	w.notFull.Broadcast()
+	w.lock.Unlock()
+
+	<-w.exit
+	return Close(w.writer)
+}
+
+func (w *AsyncLevelWriter) loop() {
+	defer close(w.exit)
+
+	var batch []asyncRecord
+	for {
+		w.lock.Lock()
+		for len(w.queue) == 0 && !w.closed {
+			w.notEmpty.Wait()
+		}
+
+		if len(w.queue) == 0 {
+			w.lock.Unlock()
+			return
+		}
+
+		batch, w.queue = w.queue, batch[:0]
+		w.records = 0
+		w.notFull.Broadcast()
+		w.lock.Unlock()
+
+		for i, r := range batch {
+			batch[i] = asyncRecord{}
+			if r.flush != nil {
+				r.flush <- Flush(w.writer)
+				continue
+			}
+
+			var err error
+			if r.leveled {
+				_, err = w.writer.WriteLevel(r.level, r.data.buf)
+			} else {
+				_, err = w.writer.Write(r.data.buf)
+			}
+			asyncBufPool.Put(r.data)
+
+			if err != nil {
+				atomic.AddUint64(&w.failed, 1)
+				if w.options.OnError != nil {
+					w.options.OnError(err)
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,180 @@
+// Copyright 2026 xgfone
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package writer
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// gateWriter records the written data, and blocks the first writing
+// until the gate is opened.
+type gateWriter struct {
+	lock    sync.Mutex
+	records []string
+	flushes int
+	closed  bool
+
+	started chan struct{}
+	gate    chan struct{}
+	once    sync.Once
+}
+
+func newGateWriter() *gateWriter {
+	return &gateWriter{started: make(chan struct{}), gate: make(chan struct{})}
+}
+
+func (w *gateWriter) Open() { close(w.gate) }
+
+func (w *gateWriter) Write(p []byte) (int, error) { return w.WriteLevel(-1, p) }
+
+func (w *gateWriter) WriteLevel(level int, p []byte) (int, error) {
+	w.once.Do(func() { close(w.started); <-w.gate })
+	if string(p) == "error" {
+		return 0, errors.New("test error")
+	}
+
+	w.lock.Lock()
+	w.records = append(w.records, fmt.Sprintf("%d:%s", level, p))
+	w.lock.Unlock()
+	return len(p), nil
+}
+
+func (w *gateWriter) Flush() error {
+	w.lock.Lock()
+	w.flushes++
+	w.lock.Unlock()
+	return nil
+}
+
+func (w *gateWriter) Close() error {
+	w.lock.Lock()
+	w.closed = true
+	w.lock.Unlock()
+	return nil
+}
+
+func (w *gateWriter) Records() string {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+	return strings.Join(w.records, ",")
+}
+
+func TestAsyncWriter(t *testing.T) {
+	gw := newGateWriter()
+	gw.Open()
+
+	var errs []error
+	w := AsyncWriter(gw, AsyncOptions{QueueSize: 2, OnError: func(err error) {
+		errs = append(errs, err)
+	}})
+
+	w.Write([]byte("a"))
+	w.WriteLevel(40, []byte("b"))
+	w.WriteLevel(40, []byte("error"))
+	w.WriteLevel(40, []byte("c"))
+	if err := w.Flush(); err != nil {
+		t.Error(err)
+	}
+
+	if records := gw.Records(); records != "-1:a,40:b,40:c" {
+		t.Errorf("unexpected records '%s'", records)
+	}
+	if gw.flushes != 1 {
+		t.Errorf("expect %d flushes, but got %d", 1, gw.flushes)
+	}
+	if n := w.Failed(); n != 1 || len(errs) != 1 {
+		t.Errorf("expect %d failed record, but got %d", 1, n)
+	}
+
+	w.WriteLevel(120, []byte("d")) // Write synchronously
+	if records := gw.Records(); records != "-1:a,40:b,40:c,120:d" {
+		t.Errorf("unexpected records '%s'", records)
+	}
+
+	for i := 0; i < 100; i++ {
+		w.WriteLevel(i, []byte("e"))
+	}
+	if err := w.Close(); err != nil {
+		t.Error(err)
+	} else if !gw.closed {
+		t.Error("expect the wrapped writer is closed, but not")
+	}
+
+	if n := len(gw.records); n != 104 {
+		t.Errorf("expect %d records, but got %d", 104, n)
+	}
+	if _, err := w.Write([]byte("f")); err != ErrAsyncClosed {
+		t.Errorf("expect the error '%v', but got '%v'", ErrAsyncClosed, err)
+	}
+}
+
+func TestAsyncWriterSyncAll(t *testing.T) {
+	gw := newGateWriter()
+	gw.Open()
+
+	w := AsyncWriter(gw, AsyncOptions{SyncLevel: -1})
+	defer w.Close()
+
+	w.WriteLevel(0, []byte("a")) // Write synchronously
+	if records := gw.Records(); records != "0:a" {
+		t.Errorf("unexpected records '%s'", records)
+	}
+}
+
+func TestAsyncWriterPolicy(t *testing.T) {
+	tests := []struct {
+		policy  AsyncPolicy
+		expect  string
+		dropped uint64
+	}{
+		{AsyncDropNewest, "0:0,1:1,2:2", 2},
+		{AsyncDropOldest, "0:0,3:3,4:4", 2},
+		{AsyncDropBelowLevel, "0:0,1:1,2:2,4:4", 1}, // 4 is blocked, not dropped.
+	}
+
+	for _, test := range tests {
+		gw := newGateWriter()
+		w := AsyncWriter(gw, AsyncOptions{QueueSize: 2, Policy: test.policy, DropLevel: 4})
+
+		w.WriteLevel(0, []byte("0"))
+		<-gw.started // The first record is being written and blocked.
+
+		for i := 1; i < 4; i++ {
+			w.WriteLevel(i, []byte(fmt.Sprint(i)))
+		}
+
+		if test.policy == AsyncDropBelowLevel {
+			done := make(chan struct{})
+			go func() { w.WriteLevel(4, []byte("4")); close(done) }()
+			gw.Open()
+			<-done
+		} else {
+			w.WriteLevel(4, []byte("4"))
+			gw.Open()
+		}
+
+		w.Close()
+		if records := gw.Records(); records != test.expect {
+			t.Errorf("policy %d: expect records '%s', but got '%s'", test.policy, test.expect, records)
+		}
+		if n := w.Dropped(); n != test.dropped {
+			t.Errorf("policy %d: expect %d dropped records, but got %d", test.policy, test.dropped, n)
+		}
+	}
+}
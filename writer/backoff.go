@@ -0,0 +1,48 @@
+// Copyright 2021 xgfone
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package writer
+
+import (
+	"math/rand"
+	"time"
+)
+
+// Backoff is the exponential backoff with the jitter to retry after failing,
+// which is not thread-safe and whose zero value is ready to use.
+type Backoff struct {
+	interval time.Duration
+}
+
+// Next returns the interval to wait for before the next retry.
+//
+// The interval starts from min, is doubled after each failure until max,
+// and is randomized by the jitter in [interval/2, interval].
+func (b *Backoff) Next(min, max time.Duration) time.Duration {
+	if b.interval == 0 {
+		b.interval = min
+	} else if b.interval *= 2; b.interval > max {
+		b.interval = max
+	}
+
+	if b.interval <= 0 {
+		return 0
+	}
+
+	half := b.interval / 2
+	return half + time.Duration(rand.Int63n(int64(half)+1))
+}
+
+// Reset resets the interval to min after succeeding.
+func (b *Backoff) Reset() { b.interval = 0 }
@@ -0,0 +1,41 @@
+// Copyright 2021 xgfone
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package writer
+
+import (
+	"testing"
+	"time"
+)
+
+func TestBackoff(t *testing.T) {
+	var backoff Backoff
+	expects := []time.Duration{100, 200, 400, 800, 1000, 1000}
+	for i, expect := range expects {
+		interval := backoff.Next(100, 1000)
+		if interval < expect/2 || interval > expect {
+			t.Errorf("%d: expect the interval in [%d, %d], but got %d",
+				i, expect/2, expect, interval)
+		}
+	}
+
+	backoff.Reset()
+	if interval := backoff.Next(100, 1000); interval < 50 || interval > 100 {
+		t.Errorf("expect the interval in [50, 100] after reset, but got %d", interval)
+	}
+
+	if interval := new(Backoff).Next(0, 0); interval != 0 {
+		t.Errorf("expect the interval 0, but got %d", interval)
+	}
+}
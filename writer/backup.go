@@ -0,0 +1,449 @@
+// Copyright 2026 xgfone
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package writer
+
+import (
+	"compress/gzip"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+const (
+	rotatedSuffix = ".rotated"
+	gzipSuffix    = ".gz"
+	tmpSuffix     = ".tmp"
+
+	// backupTimeLayout is the layout of the time in the name of the backup
+	// when rotating by the timestamp naming.
+	backupTimeLayout = "20060102T150405.000"
+)
+
+// backupWorker handles the rotated files in turn in a background goroutine,
+// which is started when there are the rotated files to be handled,
+// and exits when all of them have been done.
+//
+// For the index naming, the rotated file "NAME.rotated" is compressed as below:
+//
+//  1. Compress it to the temporary file "NAME.rotated.gz.RANDOM.tmp".
+//  2. Rename the temporary file to "NAME.rotated.gz".
+//  3. Remove the rotated file "NAME.rotated".
+//  4. Rename the backups "FILENAME.N.gz" to "FILENAME.N+1.gz" in turn.
+//  5. Rename "NAME.rotated.gz" to "FILENAME.1.gz".
+//
+// For the timestamp naming, the rotated file is the backup "FILENAME.TIMESTAMP",
+// which is compressed to "FILENAME.TIMESTAMP.gz" by the temporary file
+// if compress is true, then the oldest backups are pruned to keep the number
+// of the backups.
+//
+// So the handling can be resumed from any step after crashing.
+//
+// If Locker is set, only the steps after the compression are done with it,
+// so that the writing sharing it is not blocked by the compression.
+type backupWorker struct {
+	filename  string
+	backups   int
+	compress  bool
+	timestamp bool
+
+	// Clean is called after handling each rotated file if set.
+	Clean func()
+
+	// Rotated is called with the final name of the backup if set
+	// after the rotated file has been handled successfully.
+	Rotated func(backup string)
+
+	// Locker is used to handle each rotated file exclusively if set,
+	// for example, among the processes sharing the same file.
+	Locker sync.Locker
+
+	lock    sync.Mutex
+	wait    sync.WaitGroup
+	files   []string
+	running bool
+}
+
+func newBackupWorker(filename string, backups int, compress, timestamp bool) *backupWorker {
+	return &backupWorker{
+		filename:  filename,
+		backups:   backups,
+		compress:  compress,
+		timestamp: timestamp,
+	}
+}
+
+// Add adds the rotated files to be handled in background.
+func (w *backupWorker) Add(rotatedFiles ...string) {
+	w.lock.Lock()
+	w.files = append(w.files, rotatedFiles...)
+	if !w.running && len(w.files) > 0 {
+		w.running = true
+		w.wait.Add(1)
+		go w.loop()
+	}
+	w.lock.Unlock()
+}
+
+// Wait waits until all the rotated files have been handled.
+func (w *backupWorker) Wait() { w.wait.Wait() }
+
+// Recover removes the half-compressed files and handles the rotated
+// files left by the last running.
+func (w *backupWorker) Recover() {
+	dir, base := filepath.Split(w.filename)
+	if dir == "" {
+		dir = "."
+	}
+
+	infos, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return
+	}
+
+	var files rotatedFiles
+	var backups []string
+	prefix := base + "."
+	for _, info := range infos {
+		name := info.Name()
+		if !strings.HasPrefix(name, prefix) {
+			continue
+		}
+
+		path := filepath.Join(dir, name)
+		switch suffix := name[len(prefix):]; {
+		case strings.HasSuffix(name, tmpSuffix) && strings.Contains(name, gzipSuffix+"."):
+			os.Remove(path)
+
+		case w.timestamp:
+			if isBackupTime(suffix) {
+				backups = append(backups, path)
+			}
+
+		case strings.HasSuffix(name, rotatedSuffix+gzipSuffix):
+			files = files.add(strings.TrimSuffix(path, gzipSuffix), prefix)
+
+		case strings.HasSuffix(name, rotatedSuffix):
+			files = files.add(path, prefix)
+		}
+	}
+
+	if w.timestamp {
+		if !w.compress || len(backups) == 0 {
+			backups = []string{""} // Only prune the backups.
+		}
+		sort.Strings(backups)
+		w.Add(backups...)
+		return
+	}
+
+	sort.Sort(files)
+	for _, file := range files {
+		w.Add(file.name)
+	}
+}
+
+func (w *backupWorker) loop() {
+	defer w.wait.Done()
+	for {
+		w.lock.Lock()
+		if len(w.files) == 0 {
+			w.running = false
+			w.lock.Unlock()
+			return
+		}
+
+		rotated := w.files[0]
+		w.files = w.files[1:]
+		w.lock.Unlock()
+
+		w.handle(rotated)
+	}
+}
+
+func (w *backupWorker) handle(rotated string) {
+	// Compress the file without the lock, which is expensive.
+	var tmp string
+	if w.timestamp {
+		if w.compress && rotated != "" && fileIsExist(rotated) {
+			tmp, _ = gzipFile(rotated, rotated+gzipSuffix)
+		}
+	} else if fileIsExist(rotated) && !fileIsExist(rotated+gzipSuffix) {
+		tmp, _ = gzipFile(rotated, rotated+gzipSuffix)
+	}
+
+	if w.Locker != nil {
+		w.Locker.Lock()
+		defer w.Locker.Unlock()
+	}
+
+	var backup string
+	if w.timestamp {
+		backup = w.handleTimestamp(rotated, tmp)
+	} else {
+		backup = w.handleIndex(rotated, tmp)
+	}
+
+	if backup != "" && w.Rotated != nil {
+		w.Rotated(backup)
+	}
+
+	if w.Clean != nil {
+		w.Clean()
+	}
+}
+
+// handleIndex handles the rotated file for the index naming with
+// the temporary file compressed from it if not empty, and returns
+// the name of the backup. If failing, return "" and the rotated file
+// is left to be compressed again when recovering.
+func (w *backupWorker) handleIndex(rotated, tmp string) (backup string) {
+	gzfile := rotated + gzipSuffix
+	if tmp != "" {
+		// The rotated file may have been handled, for example, by another
+		// process while compressing it, so the compressed file is discarded.
+		if !fileIsExist(rotated) || fileIsExist(gzfile) {
+			os.Remove(tmp)
+		} else if err := os.Rename(tmp, gzfile); err != nil {
+			os.Remove(tmp)
+			return
+		}
+	}
+
+	if !fileIsExist(rotated) && !fileIsExist(gzfile) {
+		return // It has been handled, for example, by another process.
+	}
+
+	if fileIsExist(rotated) {
+		if !fileIsExist(gzfile) {
+			return // Failed to compress it.
+		}
+		if err := os.Remove(rotated); err != nil {
+			return
+		}
+	}
+
+	// The uncompressed backups "FILENAME.N" may be left before enabling
+	// the compression, which are cascaded together with "FILENAME.N.gz".
+	for i := w.backups - 1; i > 0; i-- {
+		if !w.moveIndex(i, i+1) {
+			return
+		}
+	}
+
+	w.removeIndex(1)
+	backup = fmt.Sprintf("%s.1%s", w.filename, gzipSuffix)
+	if err := os.Rename(gzfile, backup); err != nil {
+		return ""
+	}
+	return
+}
+
+// moveIndex renames the backups "FILENAME.SRC[.gz]" to "FILENAME.DST[.gz]",
+// which removes the backups with the index dst first if the former exist.
+func (w *backupWorker) moveIndex(src, dst int) bool {
+	sfn := fmt.Sprintf("%s.%d", w.filename, src)
+	gzsfn := sfn + gzipSuffix
+	if !fileIsExist(sfn) && !fileIsExist(gzsfn) {
+		return true
+	}
+
+	w.removeIndex(dst)
+	dfn := fmt.Sprintf("%s.%d", w.filename, dst)
+	for _, f := range [][2]string{{sfn, dfn}, {gzsfn, dfn + gzipSuffix}} {
+		if fileIsExist(f[0]) {
+			if err := os.Rename(f[0], f[1]); err != nil {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// removeIndex removes the backups "FILENAME.INDEX" and "FILENAME.INDEX.gz".
+func (w *backupWorker) removeIndex(index int) {
+	name := fmt.Sprintf("%s.%d", w.filename, index)
+	os.Remove(name)
+	os.Remove(name + gzipSuffix)
+}
+
+// handleTimestamp handles the backup for the timestamp naming with
+// the temporary file compressed from it if not empty, then prunes
+// the oldest backups, and returns the final name of the backup.
+// If backup is empty, only prune the backups.
+func (w *backupWorker) handleTimestamp(backup, tmp string) string {
+	if tmp != "" {
+		if !fileIsExist(backup) {
+			os.Remove(tmp) // It has been handled, for example, by another process.
+		} else if err := os.Rename(tmp, backup+gzipSuffix); err != nil {
+			os.Remove(tmp)
+		} else {
+			os.Remove(backup)
+			backup += gzipSuffix
+		}
+	}
+
+	backups := indexedBackups(w.filename)
+	for i := w.backups; i < len(backups); i++ {
+		os.Remove(backups[i])
+	}
+	return backup
+}
+
+// gzipFile compresses the file src by gzip to the temporary file
+// named like "DST.RANDOM.tmp", and returns its name, which should be
+// renamed to dst by the caller.
+func gzipFile(src, dst string) (tmp string, err error) {
+	in, err := os.Open(src)
+	if err != nil {
+		return
+	}
+	defer in.Close()
+
+	dir, base := filepath.Split(dst)
+	if dir == "" {
+		dir = "."
+	}
+
+	out, err := ioutil.TempFile(dir, base+".*"+tmpSuffix)
+	if err != nil {
+		return
+	}
+
+	tmp = out.Name()
+	defer func() {
+		if err != nil {
+			out.Close()
+			os.Remove(tmp)
+			tmp = ""
+		}
+	}()
+
+	if info, err := in.Stat(); err == nil {
+		out.Chmod(info.Mode())
+	}
+
+	gw := gzip.NewWriter(out)
+	if _, err = io.Copy(gw, in); err != nil {
+		return
+	} else if err = gw.Close(); err != nil {
+		return
+	} else if err = out.Sync(); err != nil {
+		return
+	}
+	err = out.Close()
+	return
+}
+
+type rotatedFile struct {
+	name string
+	seq  int64
+}
+
+type rotatedFiles []rotatedFile
+
+func (fs rotatedFiles) Len() int           { return len(fs) }
+func (fs rotatedFiles) Less(i, j int) bool { return fs[i].seq < fs[j].seq }
+func (fs rotatedFiles) Swap(i, j int)      { fs[i], fs[j] = fs[j], fs[i] }
+
+// add adds the rotated file like "DIR/PREFIX123.rotated" if not added.
+func (fs rotatedFiles) add(path, prefix string) rotatedFiles {
+	for _, f := range fs {
+		if f.name == path {
+			return fs
+		}
+	}
+
+	name := strings.TrimSuffix(filepath.Base(path), rotatedSuffix)
+	seq, err := strconv.ParseInt(strings.TrimPrefix(name, prefix), 10, 64)
+	if err != nil {
+		return fs
+	}
+	return append(fs, rotatedFile{name: path, seq: seq})
+}
+
+// indexedBackups returns the backups of the file rotated by the size,
+// which are sorted from the newest to the oldest, that's, the backups named
+// by the timestamp like "FILENAME.20261016T101500.123[.gz]" are sorted
+// by the timestamp descendingly, then the backups named by the index like
+// "FILENAME.N[.gz]" are sorted by the index ascendingly.
+func indexedBackups(filename string) (backups []string) {
+	dir, base := filepath.Split(filename)
+	if dir == "" {
+		dir = "."
+	}
+
+	infos, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return
+	}
+
+	var timestamps []string
+	var indexes sizedIndexes
+	prefix := base + "."
+	for _, info := range infos {
+		name := info.Name()
+		if !strings.HasPrefix(name, prefix) {
+			continue
+		}
+
+		path := filepath.Join(dir, name)
+		suffix := strings.TrimSuffix(name[len(prefix):], gzipSuffix)
+		if isBackupTime(suffix) {
+			timestamps = append(timestamps, path)
+		} else if isDigits(suffix) {
+			index, _ := strconv.Atoi(suffix)
+			indexes = append(indexes, sizedIndex{path: path, index: index})
+		}
+	}
+
+	sort.Sort(sort.Reverse(sort.StringSlice(timestamps)))
+	sort.Sort(indexes)
+
+	backups = timestamps
+	for _, index := range indexes {
+		backups = append(backups, index.path)
+	}
+	return
+}
+
+type sizedIndex struct {
+	path  string
+	index int
+}
+
+type sizedIndexes []sizedIndex
+
+func (is sizedIndexes) Len() int      { return len(is) }
+func (is sizedIndexes) Swap(i, j int) { is[i], is[j] = is[j], is[i] }
+func (is sizedIndexes) Less(i, j int) bool {
+	if is[i].index != is[j].index {
+		return is[i].index < is[j].index
+	}
+	return is[i].path < is[j].path
+}
+
+// isBackupTime reports whether s is formatted by backupTimeLayout.
+func isBackupTime(s string) bool {
+	if len(s) != len(backupTimeLayout) || s[8] != 'T' || s[15] != '.' {
+		return false
+	}
+	return isDigits(s[:8]) && isDigits(s[9:15]) && isDigits(s[16:])
+}
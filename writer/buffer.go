@@ -0,0 +1,249 @@
+// Copyright 2026 xgfone
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package writer
+
+import (
+	"errors"
+	"io"
+	"sync"
+	"time"
+)
+
+// BufferOptions is the options of BufferedLevelWriter.
+type BufferOptions struct {
+	// BufferSize is the size of the buffer.
+	//
+	// Default: 4096
+	BufferSize int
+
+	// FlushInterval is the maximum interval that the data stays in the buffer,
+	// that's, the buffer is flushed after the interval since the first data
+	// is written into the empty buffer. If less than 0, disable it.
+	//
+	// Default: time.Second
+	FlushInterval time.Duration
+
+	// FlushLevel is the level from which the buffer is flushed immediately
+	// after writing the record by WriteLevel.
+	//
+	// If 0, use the default. So set it to a negative value, such as -1,
+	// to flush the buffer after writing each record by WriteLevel.
+	//
+	// Default: LevelError (log.LvlError)
+	FlushLevel int
+}
+
+// BufferedLevelWriter is a thread-safe buffered writer, which flushes
+// the buffer into the wrapped writer when the buffer is full, after
+// the flush interval, or when writing the record with the flush level,
+// and implements the interfaces LevelWriter, WrappedWriter, Flusher
+// and io.Closer.
+//
+// If the wrapped writer has implemented the interface LevelWriter,
+// the buffered records are written into it one by one with their levels.
+// Or, the buffered data is written into it at once.
+type BufferedLevelWriter struct {
+	writer  io.Writer
+	lwriter LevelWriter // Not nil if writer has implemented LevelWriter.
+	options BufferOptions
+
+	lock    sync.Mutex
+	buffer  []byte
+	records []bufferedRecord // Only used for lwriter
+	timer   *time.Timer
+	closed  bool
+}
+
+type bufferedRecord struct {
+	level   int
+	leveled bool
+	end     int // The end offset of the record in the buffer
+}
+
+// BufferedWriter returns a new BufferedLevelWriter with the options,
+// which buffers the data written into writer.
+//
+// It is unnecessary to wrap it by SafeWriter, and writer is unnecessary
+// to be thread-safe, because it is only used with the lock.
+func BufferedWriter(writer io.Writer, options BufferOptions) *BufferedLevelWriter {
+	if writer == nil {
+		panic("BufferedWriter: the wrapped writer is nil")
+	}
+
+	if options.BufferSize <= 0 {
+		options.BufferSize = 4096
+	}
+	if options.FlushInterval == 0 {
+		options.FlushInterval = time.Second
+	}
+	if options.FlushLevel == 0 {
+		options.FlushLevel = LevelError
+	}
+
+	lwriter, _ := writer.(LevelWriter)
+	return &BufferedLevelWriter{
+		writer:  writer,
+		lwriter: lwriter,
+		options: options,
+		buffer:  make([]byte, 0, options.BufferSize),
+	}
+}
+
+// UnwrapWriter implements the interface WrappedWriter.
+func (w *BufferedLevelWriter) UnwrapWriter() io.Writer { return w.writer }
+
+// Write implements the interface io.Writer.
+func (w *BufferedLevelWriter) Write(p []byte) (n int, err error) {
+	w.lock.Lock()
+	n, err = w.write(0, false, p)
+	w.lock.Unlock()
+	return
+}
+
+// WriteLevel implements the interface LevelWriter, which flushes
+// the buffer immediately if level is not less than the flush level.
+func (w *BufferedLevelWriter) WriteLevel(level int, p []byte) (n int, err error) {
+	w.lock.Lock()
+	if n, err = w.write(level, true, p); err == nil && level >= w.options.FlushLevel {
+		err = w.flush()
+	}
+	w.lock.Unlock()
+	return
+}
+
+func (w *BufferedLevelWriter) write(level int, leveled bool, p []byte) (n int, err error) {
+	if w.closed {
+		return 0, errors.New("the buffered writer has been closed")
+	}
+	if len(p) == 0 {
+		return 0, nil
+	}
+
+	if len(w.buffer) > 0 && len(w.buffer)+len(p) > w.options.BufferSize {
+		if err = w.flush(); err != nil {
+			return
+		}
+	}
+
+	// Write the large record directly without buffering.
+	if len(p) >= w.options.BufferSize {
+		return w.writeRecord(level, leveled, p)
+	}
+
+	empty := len(w.buffer) == 0
+	w.buffer = append(w.buffer, p...)
+	if w.lwriter != nil {
+		w.records = append(w.records, bufferedRecord{
+			level: level, leveled: leveled, end: len(w.buffer),
+		})
+	}
+
+	// Start the timer when the data is written into the empty buffer.
+	if empty && w.options.FlushInterval > 0 {
+		if w.timer == nil {
+			w.timer = time.AfterFunc(w.options.FlushInterval, w.flushByTimer)
+		} else {
+			w.timer.Reset(w.options.FlushInterval)
+		}
+	}
+	return len(p), nil
+}
+
+func (w *BufferedLevelWriter) writeRecord(level int, leveled bool, p []byte) (int, error) {
+	if leveled && w.lwriter != nil {
+		return w.lwriter.WriteLevel(level, p)
+	}
+	return w.writer.Write(p)
+}
+
+// flush writes the buffered data into the wrapped writer, and keeps
+// the data failed to be written in the buffer.
+func (w *BufferedLevelWriter) flush() (err error) {
+	if len(w.buffer) == 0 {
+		return
+	}
+
+	if w.lwriter == nil {
+		var n int
+		if n, err = w.writer.Write(w.buffer); err == nil && n < len(w.buffer) {
+			err = io.ErrShortWrite
+		}
+		w.buffer = w.buffer[:copy(w.buffer, w.buffer[n:])]
+		return
+	}
+
+	var start, i int
+	for ; i < len(w.records); i++ {
+		r := w.records[i]
+		if _, err = w.writeRecord(r.level, r.leveled, w.buffer[start:r.end]); err != nil {
+			break
+		}
+		start = r.end
+	}
+
+	// Keep the records failed to be written.
+	records := w.records[:copy(w.records, w.records[i:])]
+	for j := range records {
+		records[j].end -= start
+	}
+	w.records = records
+	w.buffer = w.buffer[:copy(w.buffer, w.buffer[start:])]
+	return
+}
+
+func (w *BufferedLevelWriter) flushByTimer() {
+	w.lock.Lock()
+	if !w.closed {
+		w.flush()
+	}
+	w.lock.Unlock()
+}
+
+// Flush flushes the buffer into the wrapped writer, then flushes
+// the wrapped writer if it has implemented the interface Flusher.
+func (w *BufferedLevelWriter) Flush() (err error) {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+
+	if w.closed {
+		return nil
+	}
+
+	if err = w.flush(); err == nil {
+		err = Flush(w.writer)
+	}
+	return
+}
+
+// Close flushes the buffer and closes the wrapped writer.
+func (w *BufferedLevelWriter) Close() (err error) {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+
+	if w.closed {
+		return nil
+	}
+
+	w.closed = true
+	if w.timer != nil {
+		w.timer.Stop()
+	}
+
+	err = w.flush()
+	if cerr := Close(w.writer); err == nil {
+		err = cerr
+	}
+	return
+}
@@ -0,0 +1,105 @@
+// Copyright 2026 xgfone
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package writer
+
+import (
+	"bytes"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+type lockedBuffer struct {
+	lock sync.Mutex
+	buf  bytes.Buffer
+}
+
+func (b *lockedBuffer) Write(p []byte) (int, error) {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *lockedBuffer) String() string {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	return b.buf.String()
+}
+
+func TestBufferedWriter(t *testing.T) {
+	buf := new(lockedBuffer)
+	w := BufferedWriter(buf, BufferOptions{FlushInterval: time.Millisecond * 50})
+
+	w.WriteLevel(40, []byte("a"))
+	if s := buf.String(); s != "" {
+		t.Errorf("expect the buffer is not flushed, but got '%s'", s)
+	}
+
+	w.WriteLevel(80, []byte("b")) // Flush by the level.
+	if s := buf.String(); s != "ab" {
+		t.Errorf("expect '%s', but got '%s'", "ab", s)
+	}
+
+	w.Write([]byte("c")) // Flush by the interval.
+	for i := 0; i < 100 && buf.String() != "abc"; i++ {
+		time.Sleep(time.Millisecond * 10)
+	}
+	if s := buf.String(); s != "abc" {
+		t.Errorf("expect '%s', but got '%s'", "abc", s)
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				w.WriteLevel(40, []byte("0123456789\n"))
+			}
+		}()
+	}
+	wg.Wait()
+	w.Close()
+
+	if n := strings.Count(buf.String(), "0123456789\n"); n != 1000 {
+		t.Errorf("expect %d lines, but got %d", 1000, n)
+	}
+	if _, err := w.Write([]byte("d")); err == nil {
+		t.Errorf("expect an error after closed, but got nil")
+	}
+}
+
+func TestBufferedWriterLevel(t *testing.T) {
+	gw := newGateWriter()
+	gw.Open()
+
+	w := BufferedWriter(gw, BufferOptions{BufferSize: 8, FlushInterval: -1, FlushLevel: -1})
+	w.Write([]byte("a"))
+	w.WriteLevel(40, []byte("b")) // Flush by the level.
+	if records := gw.Records(); records != "-1:a,40:b" {
+		t.Errorf("unexpected records '%s'", records)
+	}
+
+	w = BufferedWriter(gw, BufferOptions{BufferSize: 8, FlushInterval: -1})
+	w.WriteLevel(20, []byte("cc"))
+	w.WriteLevel(40, []byte("dd"))
+	w.WriteLevel(40, []byte("eeeee")) // Flush by the full buffer.
+	w.WriteLevel(40, []byte("0123456789"))
+	w.Close()
+	if records := gw.Records(); records != "-1:a,40:b,20:cc,40:dd,40:eeeee,40:0123456789" {
+		t.Errorf("unexpected records '%s'", records)
+	}
+}
@@ -19,7 +19,9 @@ import (
 	"fmt"
 	"math"
 	"os"
+	"sync"
 	"sync/atomic"
+	"time"
 )
 
 // NewSizedRotatingFile returns a new SizedRotatingFile, which is not thread-safe.
@@ -52,6 +54,76 @@ func NewSizedRotatingFile(filename string, filesize, filenum int,
 
 // SizedRotatingFile is a file rotating logging writer based on the size.
 type SizedRotatingFile struct {
+	// If true, compress the rotated backups by gzip in background,
+	// which are named like "app.log.1.gz", "app.log.2.gz", etc.
+	// And Close will wait for the in-flight compression.
+	//
+	// The rotated file is renamed to a temporary file like
+	// "app.log.1760000000000000000.rotated" before compressed, which is
+	// compressed again when opening the file next time if failing or crashing.
+	//
+	// The uncompressed backups like "app.log.1" left before enabling it are
+	// rotated together with the compressed, so they are removed in the end.
+	//
+	// Default: false
+	Compress bool
+
+	// If true, name the backup by the rotation time in UTC, such as
+	// "app.log.20261016T101500.123", instead of the index like "app.log.1".
+	// So the rotation is only a single rename, and the oldest backups are
+	// pruned in background to keep the number of the backups, in which
+	// the existing backups named by the index are considered as older
+	// than those named by the timestamp.
+	//
+	// Default: false
+	TimestampNaming bool
+
+	// CheckInterval is the interval to check whether the file has been
+	// rotated by others, such as logrotate, when writing the data.
+	// If the file has been renamed or removed, that's, its inode has changed,
+	// reopen it. If it has been truncated, such as by "copytruncate",
+	// reset the size of the written data.
+	//
+	// If equal to or less than 0, disable the check.
+	//
+	// Default: 0
+	CheckInterval time.Duration
+
+	// OnRotate is called after rotating the file if set, where oldName is
+	// the name of the backup and newName is the name of the new file.
+	// It is used to post-process the backup, such as uploading it.
+	//
+	// Notice: if Compress or TimestampNaming is true, it is called in
+	// the background goroutine after the backup has been handled.
+	// And it is not called when the file is rotated by others.
+	//
+	// Default: nil
+	OnRotate func(oldName, newName string)
+
+	// LockFile is the sidecar lock file, such as "app.log.lock", which is
+	// used to coordinate the rotation by flock among multiple processes
+	// sharing the same file if not empty. So only one process rotates
+	// the file, and the others detect the rotation and reopen the file.
+	//
+	// Notice: each writing acquires the shared lock and checks the file by stat,
+	// so that no process writes the file after it has been rotated and handed
+	// to the background compression, which compresses the backup without
+	// the lock and only renames it with the exclusive lock. And it is only
+	// supported on the unix-like platforms, such as Linux, macOS and BSD.
+	//
+	// Default: ""
+	LockFile string
+
+	// Retention is the additional retention policy of the backups
+	// besides the number of the backups.
+	Retention
+
+	lock        *fileLock
+	worker      *backupWorker
+	cleaning    sync.Mutex // Serialize the retention by the writing and the worker.
+	info        os.FileInfo
+	checkAt     time.Time
+	reopen      int32
 	file        *os.File
 	filemode    os.FileMode
 	filename    string
@@ -65,10 +137,26 @@ type SizedRotatingFile struct {
 func (f *SizedRotatingFile) Close() (err error) {
 	if atomic.CompareAndSwapInt32(&f.closed, 0, 1) {
 		err = f.close()
+		if f.worker != nil {
+			f.worker.Wait()
+		}
+		if f.lock != nil {
+			f.lock.Close()
+		}
 	}
 	return
 }
 
+// Reopen reopens the file when writing the data next time, which is used
+// after the file has been rotated by others, such as logrotate.
+//
+// It is safe to be called concurrently with Write, for example,
+// in the goroutine started by ReopenOnSignal.
+func (f *SizedRotatingFile) Reopen() error {
+	atomic.StoreInt32(&f.reopen, 1)
+	return nil
+}
+
 // Flush flushes the data to the underlying disk.
 func (f *SizedRotatingFile) Flush() (err error) {
 	if f.file != nil {
@@ -83,10 +171,41 @@ func (f *SizedRotatingFile) Write(data []byte) (n int, err error) {
 		return 0, errors.New("the file has been closed")
 	}
 
+	if atomic.CompareAndSwapInt32(&f.reopen, 1, 0) || (f.lock == nil && f.isRotatedByOthers()) {
+		if err = f.close(); err != nil {
+			return 0, fmt.Errorf("failed to close the rotating file '%s': %s", f.filename, err)
+		}
+	}
+
 	if f.file == nil {
+		if f.LockFile != "" && f.lock == nil {
+			if f.lock, err = newFileLock(f.LockFile); err != nil {
+				return 0, fmt.Errorf("failed to open the lock file '%s': %s", f.LockFile, err)
+			}
+		}
+
+		if (f.Compress || f.TimestampNaming) && f.worker == nil && f.backupCount > 0 {
+			f.worker = newBackupWorker(f.filename, f.backupCount, f.Compress, f.TimestampNaming)
+			f.worker.Clean = f.clean
+			f.worker.Rotated = f.rotated
+			if f.lock != nil {
+				f.worker.Locker = f.lock
+				f.lock.Lock()
+				f.worker.Recover()
+				f.lock.Unlock()
+			} else {
+				f.worker.Recover()
+			}
+		}
+
 		if err = f.open(); err != nil {
 			return
 		}
+		f.clean()
+	}
+
+	if f.lock != nil {
+		return f.writeShared(data)
 	}
 
 	if f.nbytes+len(data) > f.maxSize {
@@ -117,9 +236,102 @@ func (f *SizedRotatingFile) open() (err error) {
 
 	f.nbytes = int(info.Size())
 	f.file = file
+	f.info = info
+	f.checkAt = time.Now().Add(f.CheckInterval)
 	return
 }
 
+// isRotatedByOthers checks whether the file has been rotated by others
+// every CheckInterval, or for each writing if LockFile is set,
+// and reports whether the file needs to be reopened.
+func (f *SizedRotatingFile) isRotatedByOthers() bool {
+	if f.file == nil {
+		return false
+	}
+
+	if f.lock == nil {
+		if f.CheckInterval <= 0 {
+			return false
+		}
+
+		now := time.Now()
+		if now.Before(f.checkAt) {
+			return false
+		}
+		f.checkAt = now.Add(f.CheckInterval)
+	}
+
+	info, err := os.Stat(f.filename)
+	if err != nil || !os.SameFile(info, f.info) {
+		return true // The file has been renamed or removed.
+	}
+
+	// The file has been truncated, or written by other processes.
+	if size := int(info.Size()); size < f.nbytes || f.lock != nil {
+		f.nbytes = size
+	}
+	return false
+}
+
+// writeShared writes the data with the shared lock among the processes,
+// so that the file is not rotated by other processes during writing.
+func (f *SizedRotatingFile) writeShared(data []byte) (n int, err error) {
+	for rotated := false; ; rotated = true {
+		f.lock.RLock()
+		if f.isRotatedByOthers() {
+			if err = f.close(); err != nil {
+				err = fmt.Errorf("failed to close the rotating file '%s': %s", f.filename, err)
+			} else {
+				err = f.open()
+			}
+
+			if err != nil {
+				f.lock.RUnlock()
+				return
+			}
+		}
+
+		if !rotated && f.nbytes+len(data) > f.maxSize {
+			f.lock.RUnlock()
+			if err = f.rollover(len(data)); err != nil {
+				return
+			}
+			continue
+		}
+
+		n, err = f.file.Write(data)
+		f.nbytes += n
+		f.lock.RUnlock()
+		return
+	}
+}
+
+// rollover rotates the file exclusively among the processes.
+func (f *SizedRotatingFile) rollover(n int) (err error) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	// The file may have been rotated by another process during waiting.
+	if f.isRotatedByOthers() {
+		if err = f.close(); err != nil {
+			return fmt.Errorf("failed to close the rotating file '%s': %s", f.filename, err)
+		} else if err = f.open(); err != nil {
+			return
+		}
+	}
+
+	if f.nbytes+n > f.maxSize {
+		err = f.doRollover()
+	}
+	return
+}
+
+func (f *SizedRotatingFile) rotated(backup string) {
+	if f.OnRotate != nil {
+		f.OnRotate(backup, f.filename)
+	}
+}
+
 func (f *SizedRotatingFile) close() (err error) {
 	if f.file != nil {
 		err = f.file.Close()
@@ -143,6 +355,10 @@ func (f *SizedRotatingFile) doRollover() (err error) {
 			return nil
 		}
 
+		if f.worker != nil {
+			return f.rotateInBackground()
+		}
+
 		for _, i := range ranges(f.backupCount-1, 0, -1) {
 			sfn := fmt.Sprintf("%s.%d", f.filename, i)
 			dfn := fmt.Sprintf("%s.%d", f.filename, i+1)
@@ -170,12 +386,47 @@ func (f *SizedRotatingFile) doRollover() (err error) {
 			}
 		}
 
-		err = f.open()
+		if err = f.open(); err == nil {
+			f.rotated(dfn)
+			f.clean()
+		}
 	}
 
 	return
 }
 
+func (f *SizedRotatingFile) clean() {
+	if f.Retention.enabled() {
+		f.cleaning.Lock()
+		defer f.cleaning.Unlock()
+		f.Retention.clean(indexedBackups(f.filename), time.Now())
+	}
+}
+
+// rotateInBackground renames the current file to the backup named by
+// the timestamp or to a temporary file, then opens a new file. The backup
+// or the temporary file will be handled in background.
+func (f *SizedRotatingFile) rotateInBackground() (err error) {
+	var rotated string
+	if now := time.Now().UTC(); f.TimestampNaming {
+		rotated = f.filename + "." + now.Format(backupTimeLayout)
+		for fileIsExist(rotated) || fileIsExist(rotated+gzipSuffix) {
+			now = now.Add(time.Millisecond)
+			rotated = f.filename + "." + now.Format(backupTimeLayout)
+		}
+	} else {
+		rotated = fmt.Sprintf("%s.%d%s", f.filename, now.UnixNano(), rotatedSuffix)
+	}
+
+	if err = os.Rename(f.filename, rotated); err != nil {
+		return fmt.Errorf("failed to rename the rotating file '%s' to '%s': %s",
+			f.filename, rotated, err)
+	}
+
+	f.worker.Add(rotated)
+	return f.open()
+}
+
 func fileIsExist(name string) bool {
 	if _, err := os.Stat(name); err != nil {
 		if os.IsNotExist(err) {
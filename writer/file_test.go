@@ -15,8 +15,13 @@
 package writer
 
 import (
+	"compress/gzip"
+	"io/ioutil"
 	"os"
+	"path/filepath"
+	"strings"
 	"testing"
+	"time"
 )
 
 func TestSizedRotatingFile(t *testing.T) {
@@ -68,3 +73,274 @@ func TestSizedRotatingFile(t *testing.T) {
 		}
 	}
 }
+
+func readGzipFile(t *testing.T, filename string) string {
+	f, err := os.Open(filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	r, err := gzip.NewReader(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := ioutil.ReadAll(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(data)
+}
+
+func TestSizedRotatingFileCompress(t *testing.T) {
+	dir, err := ioutil.TempDir("", "sized_rotating_file")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	filename := filepath.Join(dir, "app.log")
+
+	// The files left by the last running.
+	ioutil.WriteFile(filename+".100.rotated", []byte("old1"), 0644)
+	ioutil.WriteFile(filename+".100.rotated.gz.tmp", []byte("broken"), 0644)
+	ioutil.WriteFile(filename+".200.rotated", []byte("old2"), 0644)
+
+	file := NewSizedRotatingFile(filename, 10, 3)
+	file.Compress = true
+	for _, data := range []string{"0123456789", "abcdefghij", "ABCDEFGHIJ", "klmno"} {
+		if _, err := file.Write([]byte(data)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	file.Close()
+
+	files := listdir(dir, "app.log")
+	if len(files) != 4 {
+		t.Errorf("expect %d log files, but got %d: %v", 4, len(files), files)
+	}
+
+	expects := map[string]string{
+		"app.log.1.gz": "ABCDEFGHIJ",
+		"app.log.2.gz": "abcdefghij",
+		"app.log.3.gz": "0123456789",
+	}
+	for name, expect := range expects {
+		if data := readGzipFile(t, filepath.Join(dir, name)); data != expect {
+			t.Errorf("%s: expect '%s', but got '%s'", name, expect, data)
+		}
+	}
+
+	if data, err := ioutil.ReadFile(filename); err != nil {
+		t.Error(err)
+	} else if string(data) != "klmno" {
+		t.Errorf("expect '%s', but got '%s'", "klmno", data)
+	}
+}
+
+func TestSizedRotatingFileEnableCompress(t *testing.T) {
+	dir, err := ioutil.TempDir("", "sized_rotating_file")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	// The uncompressed backups are left before enabling the compression.
+	filename := filepath.Join(dir, "app.log")
+	ioutil.WriteFile(filename+".1", []byte("old1"), 0644)
+	ioutil.WriteFile(filename+".2", []byte("old2"), 0644)
+
+	file := NewSizedRotatingFile(filename, 10, 2)
+	file.Compress = true
+	for _, data := range []string{"0123456789", "abcdefghij", "klmno"} {
+		if _, err := file.Write([]byte(data)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	file.Close()
+
+	files := listdir(dir, "app.log")
+	if len(files) != 3 || files["app.log.1.gz"] == 0 || files["app.log.2.gz"] == 0 {
+		t.Errorf("unexpected files: %v", files)
+	}
+}
+
+// compressLocker checks that the file has been compressed before locking.
+type compressLocker struct {
+	dir    string
+	locked int
+	tmps   int
+}
+
+func (l *compressLocker) Lock() {
+	l.locked++
+	tmps, _ := filepath.Glob(filepath.Join(l.dir, "*.gz.*.tmp"))
+	l.tmps += len(tmps)
+}
+
+func (l *compressLocker) Unlock() {}
+
+func TestBackupWorkerCompressWithoutLock(t *testing.T) {
+	dir, err := ioutil.TempDir("", "backup_worker")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	filename := filepath.Join(dir, "app.log")
+	ioutil.WriteFile(filename+".100.rotated", []byte("data"), 0644)
+
+	locker := &compressLocker{dir: dir}
+	worker := newBackupWorker(filename, 3, true, false)
+	worker.Locker = locker
+	worker.Add(filename + ".100.rotated")
+	worker.Wait()
+
+	if locker.locked != 1 || locker.tmps != 1 {
+		t.Errorf("expect to compress the file before locking, but got %d/%d", locker.tmps, locker.locked)
+	}
+
+	if files := listdir(dir, "app.log"); len(files) != 1 || files["app.log.1.gz"] == 0 {
+		t.Errorf("unexpected files: %v", files)
+	} else if data := readGzipFile(t, filepath.Join(dir, "app.log.1.gz")); data != "data" {
+		t.Errorf("expect '%s', but got '%s'", "data", data)
+	}
+}
+
+func TestSizedRotatingFileTimestampNaming(t *testing.T) {
+	for _, compress := range []bool{false, true} {
+		testSizedRotatingFileTimestampNaming(t, compress)
+	}
+}
+
+func testSizedRotatingFileTimestampNaming(t *testing.T, compress bool) {
+	dir, err := ioutil.TempDir("", "sized_rotating_file")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	filename := filepath.Join(dir, "app.log")
+
+	// The backups named by the index are older than those by the timestamp.
+	ioutil.WriteFile(filename+".1", []byte("old1"), 0644)
+	ioutil.WriteFile(filename+".2", []byte("old2"), 0644)
+
+	file := NewSizedRotatingFile(filename, 10, 3)
+	file.Compress = compress
+	file.TimestampNaming = true
+	for _, data := range []string{"0123456789", "abcdefghij", "ABCDEFGHIJ", "klmno"} {
+		if _, err := file.Write([]byte(data)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	file.Close()
+
+	backups := indexedBackups(filename)
+	if len(backups) != 3 {
+		t.Fatalf("compress=%v: expect %d backups, but got %d: %v",
+			compress, 3, len(backups), backups)
+	}
+
+	for i, expect := range []string{"ABCDEFGHIJ", "abcdefghij", "0123456789"} {
+		var data string
+		if compress {
+			if !strings.HasSuffix(backups[i], gzipSuffix) {
+				t.Errorf("expect a compressed backup, but got '%s'", backups[i])
+				continue
+			}
+			data = readGzipFile(t, backups[i])
+		} else if bs, err := ioutil.ReadFile(backups[i]); err != nil {
+			t.Error(err)
+		} else {
+			data = string(bs)
+		}
+
+		if data != expect {
+			t.Errorf("compress=%v: %s: expect '%s', but got '%s'",
+				compress, backups[i], expect, data)
+		}
+	}
+
+	if data, err := ioutil.ReadFile(filename); err != nil {
+		t.Error(err)
+	} else if string(data) != "klmno" {
+		t.Errorf("expect '%s', but got '%s'", "klmno", data)
+	}
+}
+
+func TestSizedRotatingFileReopen(t *testing.T) {
+	dir, err := ioutil.TempDir("", "sized_rotating_file")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	filename := filepath.Join(dir, "app.log")
+	file := NewSizedRotatingFile(filename, 10, 3)
+	file.CheckInterval = time.Nanosecond
+	defer file.Close()
+
+	var rotations []string
+	file.OnRotate = func(oldName, newName string) {
+		rotations = append(rotations, filepath.Base(oldName)+" -> "+filepath.Base(newName))
+	}
+
+	write := func(data string) {
+		if _, err := file.Write([]byte(data)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	readfile := func(name string) string {
+		data, err := ioutil.ReadFile(filepath.Join(dir, name))
+		if err != nil {
+			t.Error(err)
+		}
+		return string(data)
+	}
+
+	// Rotated by others with "create".
+	write("abc")
+	if err := os.Rename(filename, filename+".moved"); err != nil {
+		t.Fatal(err)
+	}
+	time.Sleep(time.Millisecond)
+	write("def")
+	if data := readfile("app.log.moved"); data != "abc" {
+		t.Errorf("expect '%s', but got '%s'", "abc", data)
+	}
+	if data := readfile("app.log"); data != "def" {
+		t.Errorf("expect '%s', but got '%s'", "def", data)
+	}
+
+	// Rotated by others with "copytruncate".
+	if err := os.Truncate(filename, 0); err != nil {
+		t.Fatal(err)
+	}
+	time.Sleep(time.Millisecond)
+	write("0123456789") // No rotation because the file has been truncated.
+	if data := readfile("app.log"); data != "0123456789" {
+		t.Errorf("expect '%s', but got '%s'", "0123456789", data)
+	}
+
+	// Reopen explicitly.
+	file.CheckInterval = 0
+	if err := os.Rename(filename, filename+".moved"); err != nil {
+		t.Fatal(err)
+	}
+	file.Reopen()
+	write("ghi")
+	write("jklmnopq") // Rotate by the size.
+	if data := readfile("app.log.1"); data != "ghi" {
+		t.Errorf("expect '%s', but got '%s'", "ghi", data)
+	}
+	if data := readfile("app.log"); data != "jklmnopq" {
+		t.Errorf("expect '%s', but got '%s'", "jklmnopq", data)
+	}
+
+	if len(rotations) != 1 || rotations[0] != "app.log.1 -> app.log" {
+		t.Errorf("unexpected rotations: %v", rotations)
+	}
+}
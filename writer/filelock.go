@@ -0,0 +1,81 @@
+// Copyright 2026 xgfone
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build darwin || dragonfly || freebsd || linux || netbsd || openbsd
+// +build darwin dragonfly freebsd linux netbsd openbsd
+
+package writer
+
+import (
+	"os"
+	"sync"
+	"syscall"
+)
+
+// fileLock is a lock among the goroutines and the processes
+// based on flock of the sidecar lock file.
+//
+// Notice: the shared lock must not be held by more than one goroutine
+// in the same process at the same time, because flock is released by
+// any of them.
+type fileLock struct {
+	lock sync.RWMutex
+	file *os.File
+}
+
+func newFileLock(filename string) (*fileLock, error) {
+	file, err := os.OpenFile(filename, os.O_CREATE|os.O_RDWR, 0644)
+	if err != nil {
+		return nil, err
+	}
+	return &fileLock{file: file}, nil
+}
+
+// Lock acquires the exclusive lock in the current process firstly,
+// because flock does not exclude the goroutines sharing the same file
+// descriptor, then acquires the exclusive lock among the processes.
+func (l *fileLock) Lock() {
+	l.lock.Lock()
+	l.flock(syscall.LOCK_EX)
+}
+
+// Unlock releases the exclusive lock.
+func (l *fileLock) Unlock() {
+	l.flock(syscall.LOCK_UN)
+	l.lock.Unlock()
+}
+
+// RLock acquires the shared lock.
+func (l *fileLock) RLock() {
+	l.lock.RLock()
+	l.flock(syscall.LOCK_SH)
+}
+
+// RUnlock releases the shared lock.
+func (l *fileLock) RUnlock() {
+	l.flock(syscall.LOCK_UN)
+	l.lock.RUnlock()
+}
+
+func (l *fileLock) flock(how int) {
+	for {
+		err := syscall.Flock(int(l.file.Fd()), how)
+		if err != syscall.EINTR {
+			break
+		}
+	}
+}
+
+// Close closes the lock file.
+func (l *fileLock) Close() error { return l.file.Close() }
@@ -0,0 +1,32 @@
+// Copyright 2026 xgfone
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build !darwin && !dragonfly && !freebsd && !linux && !netbsd && !openbsd
+// +build !darwin,!dragonfly,!freebsd,!linux,!netbsd,!openbsd
+
+package writer
+
+import (
+	"fmt"
+	"runtime"
+	"sync"
+)
+
+type fileLock struct{ sync.RWMutex }
+
+func newFileLock(filename string) (*fileLock, error) {
+	return nil, fmt.Errorf("the lock file is not supported on %s", runtime.GOOS)
+}
+
+func (l *fileLock) Close() error { return nil }
@@ -0,0 +1,154 @@
+// Copyright 2026 xgfone
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build darwin || dragonfly || freebsd || linux || netbsd || openbsd
+// +build darwin dragonfly freebsd linux netbsd openbsd
+
+package writer
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+const (
+	lockTestDirEnv  = "GO_LOG_TEST_LOCK_DIR"
+	lockTestKindEnv = "GO_LOG_TEST_LOCK_KIND"
+	lockTestIDEnv   = "GO_LOG_TEST_LOCK_ID"
+
+	lockTestLines = 300
+)
+
+// TestLockChild is the child process spawned by testRotatingFileLock,
+// which is skipped when running the tests directly.
+func TestLockChild(t *testing.T) {
+	dir := os.Getenv(lockTestDirEnv)
+	if dir == "" {
+		t.Skip("not the child process")
+	}
+
+	var w io.WriteCloser
+	switch kind := os.Getenv(lockTestKindEnv); kind {
+	case "sized":
+		file := NewSizedRotatingFile(filepath.Join(dir, "app.log"), 1000, 100)
+		file.LockFile = filepath.Join(dir, "app.log.lock")
+		w = file
+	case "compress":
+		file := NewSizedRotatingFile(filepath.Join(dir, "app.log"), 1000, 100)
+		file.LockFile = filepath.Join(dir, "app.log.lock")
+		file.Compress = true
+		w = file
+	case "timed":
+		file := NewTimedRotatingFile(filepath.Join(dir, "app.%Y%m%d.log"), 0)
+		file.LockFile = filepath.Join(dir, "app.lock")
+		file.MaxSize = 1000
+		w = file
+	default:
+		t.Fatalf("unknown writer kind '%s'", kind)
+	}
+	defer w.Close()
+
+	id := os.Getenv(lockTestIDEnv)
+	for i := 0; i < lockTestLines; i++ {
+		line := fmt.Sprintf("%s %04d %s\n", id, i, strings.Repeat("x", 20))
+		if _, err := w.Write([]byte(line)); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestSizedRotatingFileLock(t *testing.T)    { testRotatingFileLock(t, "sized") }
+func TestTimedRotatingFileLock(t *testing.T)    { testRotatingFileLock(t, "timed") }
+func TestCompressRotatingFileLock(t *testing.T) { testRotatingFileLock(t, "compress") }
+
+func testRotatingFileLock(t *testing.T, kind string) {
+	if os.Getenv(lockTestDirEnv) != "" {
+		t.Skip("in the child process")
+	}
+
+	dir, err := ioutil.TempDir("", "rotating_file_lock")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	const procs = 4
+	cmds := make([]*exec.Cmd, procs)
+	outputs := make([]bytes.Buffer, procs)
+	for i := range cmds {
+		cmd := exec.Command(os.Args[0], "-test.run=^TestLockChild$", "-test.count=1")
+		cmd.Env = append(os.Environ(),
+			lockTestDirEnv+"="+dir,
+			lockTestKindEnv+"="+kind,
+			lockTestIDEnv+"="+strconv.Itoa(i))
+		cmd.Stdout = &outputs[i]
+		cmd.Stderr = &outputs[i]
+		if err := cmd.Start(); err != nil {
+			t.Fatal(err)
+		}
+		cmds[i] = cmd
+	}
+
+	for i, cmd := range cmds {
+		if err := cmd.Wait(); err != nil {
+			t.Fatalf("child process %d: %s: %s", i, err, outputs[i].String())
+		}
+	}
+
+	counts := make(map[string]int, procs)
+	for name := range listdir(dir, "app.") {
+		if strings.HasSuffix(name, ".lock") {
+			continue
+		}
+
+		file, err := os.Open(filepath.Join(dir, name))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		var r io.Reader = file
+		if strings.HasSuffix(name, gzipSuffix) {
+			if r, err = gzip.NewReader(file); err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		scanner := bufio.NewScanner(r)
+		for scanner.Scan() {
+			line := scanner.Text()
+			if fields := strings.Fields(line); len(fields) != 3 || len(line) != 27 {
+				t.Errorf("%s: invalid line '%s'", name, line)
+			} else {
+				counts[fields[0]]++
+			}
+		}
+		file.Close()
+	}
+
+	for i := 0; i < procs; i++ {
+		if n := counts[strconv.Itoa(i)]; n != lockTestLines {
+			t.Errorf("%s: process %d: expect %d lines, but got %d", kind, i, lockTestLines, n)
+		}
+	}
+}
@@ -0,0 +1,185 @@
+// Copyright 2026 xgfone
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package fluent provides the encoder and writer to send the log record
+// to fluentd or fluent-bit by the Forward protocol.
+package fluent
+
+import (
+	"encoding/binary"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/xgfone/go-log/encoder"
+)
+
+var (
+	_ encoder.Int64Encoder       = &Encoder{}
+	_ encoder.Uint64Encoder      = &Encoder{}
+	_ encoder.Float64Encoder     = &Encoder{}
+	_ encoder.BoolEncoder        = &Encoder{}
+	_ encoder.StringEncoder      = &Encoder{}
+	_ encoder.TimeEncoder        = &Encoder{}
+	_ encoder.DurationEncoder    = &Encoder{}
+	_ encoder.StringSliceEncoder = &Encoder{}
+)
+
+// Encoder is a log encoder to encode the log record as the MessagePack
+// Forward message "[tag, time, record]", which is used with Writer.
+//
+//   - The tag is Tag suffixed by the logger name, such as "app.db".
+//   - The time is the EventTime with the nanoseconds.
+//   - The record is the map of the level, the key-values and the message.
+//
+// The nested object and array are flattened, so the key of the nested field
+// is like "a.b". And the value is encoded as the MessagePack type,
+// such as the integer, the float and the string.
+type Encoder struct {
+	// Tag is the tag of the Forward message, which is suffixed by
+	// the logger name with "." if the logger name is not empty.
+	//
+	// Default: filepath.Base(os.Args[0])
+	Tag string
+
+	// LevelKey is the key name of the level if not empty.
+	//
+	// Default: "lvl"
+	LevelKey string
+
+	// MsgKey is the key name of the message.
+	//
+	// Default: "msg"
+	MsgKey string
+}
+
+// NewEncoder returns a new fluent encoder.
+func NewEncoder() *Encoder {
+	return &Encoder{
+		Tag:      filepath.Base(os.Args[0]),
+		LevelKey: "lvl",
+		MsgKey:   "msg",
+	}
+}
+
+// Start implements the interface log.Encoder.
+//
+// Notice: the record must be encoded from the beginning of the buffer,
+// because the number of the fields is filled back by End.
+func (enc *Encoder) Start(buf []byte, name, level string) []byte {
+	tag := enc.Tag
+	switch {
+	case name == "":
+	case tag == "":
+		tag = name
+	default:
+		tag = tag + "." + name
+	}
+
+	buf = append(buf, 0x93) // [tag, time, record]
+	buf = appendString(buf, tag)
+	buf = appendEventTime(buf, encoder.Now())
+	buf = append(buf, 0xdf, 0, 0, 0, 0) // map32, filled back by End
+
+	if enc.LevelKey != "" {
+		buf = appendString(buf, enc.LevelKey)
+		buf = appendString(buf, level)
+	}
+	return buf
+}
+
+// End implements the interface log.Encoder.
+func (enc *Encoder) End(buf []byte, msg string) []byte {
+	buf = appendString(buf, enc.MsgKey)
+	buf = appendString(buf, msg)
+
+	start := recordStart(buf)
+	if start < 0 { // The record is not started by Encoder.
+		return buf
+	}
+
+	var n uint32
+	for i, _len := start+5, len(buf); i >= 0 && i < _len; n++ {
+		i = skipValue(buf, skipValue(buf, i))
+	}
+	binary.BigEndian.PutUint32(buf[start+1:], n)
+	return buf
+}
+
+// recordStart returns the position of the record map in the message
+// started by Encoder, or -1 if the message is invalid.
+func recordStart(msg []byte) int {
+	if len(msg) == 0 || msg[0] != 0x93 {
+		return -1
+	}
+
+	_, i := readString(msg, 1)
+	if i < 0 || i+15 > len(msg) || msg[i] != 0xd7 || msg[i+10] != 0xdf {
+		return -1
+	}
+	return i + 10
+}
+
+// Encode implements the interface log.Encoder.
+func (enc *Encoder) Encode(buf []byte, key string, value interface{}) []byte {
+	switch v := value.(type) {
+	case encoder.ObjectMarshaler:
+		return encoder.EncodeObject(buf, enc, key, v)
+	case encoder.ArrayMarshaler:
+		return encoder.EncodeArray(buf, enc, key, v)
+	default:
+		buf = appendString(buf, key)
+		return appendAny(buf, value)
+	}
+}
+
+// EncodeInt64 implements the interface encoder.Int64Encoder.
+func (enc *Encoder) EncodeInt64(dst []byte, key string, value int64) []byte {
+	return appendInt(appendString(dst, key), value)
+}
+
+// EncodeUint64 implements the interface encoder.Uint64Encoder.
+func (enc *Encoder) EncodeUint64(dst []byte, key string, value uint64) []byte {
+	return appendUint(appendString(dst, key), value)
+}
+
+// EncodeFloat64 implements the interface encoder.Float64Encoder.
+func (enc *Encoder) EncodeFloat64(dst []byte, key string, value float64) []byte {
+	return appendFloat(appendString(dst, key), value)
+}
+
+// EncodeBool implements the interface encoder.BoolEncoder.
+func (enc *Encoder) EncodeBool(dst []byte, key string, value bool) []byte {
+	return appendBool(appendString(dst, key), value)
+}
+
+// EncodeString implements the interface encoder.StringEncoder.
+func (enc *Encoder) EncodeString(dst []byte, key string, value string) []byte {
+	return appendString(appendString(dst, key), value)
+}
+
+// EncodeTime implements the interface encoder.TimeEncoder.
+func (enc *Encoder) EncodeTime(dst []byte, key string, value time.Time) []byte {
+	return appendString(appendString(dst, key), value.Format(time.RFC3339Nano))
+}
+
+// EncodeDuration implements the interface encoder.DurationEncoder.
+func (enc *Encoder) EncodeDuration(dst []byte, key string, value time.Duration) []byte {
+	return appendString(appendString(dst, key), value.String())
+}
+
+// EncodeStringSlice implements the interface encoder.StringSliceEncoder.
+func (enc *Encoder) EncodeStringSlice(dst []byte, key string, value []string) []byte {
+	return appendAny(appendString(dst, key), value)
+}
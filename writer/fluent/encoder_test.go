@@ -0,0 +1,198 @@
+// Copyright 2026 xgfone
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package fluent
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"math"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/xgfone/go-log"
+	"github.com/xgfone/go-log/encoder"
+)
+
+type eventTime struct{ sec, nsec uint32 }
+
+// decode decodes the MessagePack value at i for the test,
+// and returns the value and the position after it.
+func decode(b []byte, i int) (interface{}, int) {
+	c := b[i]
+	switch {
+	case c <= 0x7f:
+		return int64(c), i + 1
+	case c >= 0xe0:
+		return int64(int8(c)), i + 1
+	case c <= 0x8f, c == 0xde, c == 0xdf:
+		var n int
+		switch c {
+		case 0xde:
+			n, i = readLength(b, i+1, 2)
+		case 0xdf:
+			n, i = readLength(b, i+1, 4)
+		default:
+			n, i = int(c&0x0f), i+1
+		}
+
+		m := make(map[string]interface{}, n)
+		for ; n > 0; n-- {
+			var k, v interface{}
+			k, i = decode(b, i)
+			v, i = decode(b, i)
+			m[k.(string)] = v
+		}
+		return m, i
+	case c <= 0x9f, c == 0xdc, c == 0xdd:
+		var n int
+		switch c {
+		case 0xdc:
+			n, i = readLength(b, i+1, 2)
+		case 0xdd:
+			n, i = readLength(b, i+1, 4)
+		default:
+			n, i = int(c&0x0f), i+1
+		}
+
+		a := make([]interface{}, n)
+		for j := range a {
+			a[j], i = decode(b, i)
+		}
+		return a, i
+	case c <= 0xbf, c == 0xd9, c == 0xda, c == 0xdb:
+		return readString(b, i)
+	case c == 0xc0:
+		return nil, i + 1
+	case c == 0xc2, c == 0xc3:
+		return c == 0xc3, i + 1
+	case c == 0xc4, c == 0xc5, c == 0xc6:
+		n, j := readLength(b, i+1, 1<<(c-0xc4))
+		return b[j : j+n], j + n
+	case c == 0xcb:
+		return math.Float64frombits(binary.BigEndian.Uint64(b[i+1:])), i + 9
+	case c >= 0xcc && c <= 0xcf:
+		var u uint64
+		n := 1 << (c - 0xcc)
+		for _, x := range b[i+1 : i+1+n] {
+			u = u<<8 | uint64(x)
+		}
+		return int64(u), i + 1 + n
+	case c >= 0xd0 && c <= 0xd3:
+		var u uint64
+		n := 1 << (c - 0xd0)
+		for _, x := range b[i+1 : i+1+n] {
+			u = u<<8 | uint64(x)
+		}
+		shift := uint(64 - 8*n)
+		return int64(u<<shift) >> shift, i + 1 + n
+	case c == 0xd7 && b[i+1] == 0:
+		return eventTime{binary.BigEndian.Uint32(b[i+2:]), binary.BigEndian.Uint32(b[i+6:])}, i + 10
+	default:
+		panic("unsupported msgpack type")
+	}
+}
+
+type object struct{}
+
+func (object) MarshalLogObject(enc encoder.FieldEncoder) {
+	enc.AddAny("k1", 1)
+	enc.AddAny("k2", "v2")
+}
+
+func TestEncoder(t *testing.T) {
+	defer func(now func() time.Time) { encoder.Now = now }(encoder.Now)
+	encoder.Now = func() time.Time { return time.Unix(1700000000, 123) }
+
+	enc := NewEncoder()
+	enc.Tag = "app"
+
+	buf := bytes.NewBuffer(nil)
+	logger := log.New("db").WithWriter(buf).WithEncoder(enc).
+		WithContexts("ctx", "value").WithLevel(log.LvlInfo)
+
+	logger.Info().
+		Int("int", -100).
+		Uint64("uint", 300).
+		Float64("float", 1.5).
+		Bool("bool", true).
+		Str("str", "abc").
+		StrSlice("strs", []string{"a", "b"}).
+		Kv("nil", nil).
+		Kv("err", errors.New("error")).
+		Kv("map", map[string]interface{}{"a": int64(1)}).
+		Kv("obj", object{}).
+		Printf("msg")
+
+	p := buf.Bytes()
+	if end := skipValue(p, 0); end != len(p) {
+		t.Fatalf("invalid message: %x", p)
+	}
+
+	v, _ := decode(p, 0)
+	expect := []interface{}{
+		"app.db",
+		eventTime{1700000000, 123},
+		map[string]interface{}{
+			"lvl":    "info",
+			"ctx":    "value",
+			"int":    int64(-100),
+			"uint":   int64(300),
+			"float":  1.5,
+			"bool":   true,
+			"str":    "abc",
+			"strs":   []interface{}{"a", "b"},
+			"nil":    nil,
+			"err":    "error",
+			"map":    map[string]interface{}{"a": int64(1)},
+			"obj.k1": int64(1),
+			"obj.k2": "v2",
+			"msg":    "msg",
+		},
+	}
+
+	if !reflect.DeepEqual(v, expect) {
+		t.Errorf("expect '%v', but got '%v'", expect, v)
+	}
+}
+
+func TestSkipValue(t *testing.T) {
+	var buf []byte
+	buf = appendString(buf, string(make([]byte, 300)))
+	buf = appendBinary(buf, make([]byte, 70000))
+	buf = appendInt(buf, math.MinInt64)
+	buf = appendUint(buf, math.MaxUint32)
+	array := len(buf)
+	buf = appendArrayHeader(buf, 20)
+	for i := 0; i < 20; i++ {
+		buf = appendInt(buf, int64(-i*10))
+	}
+
+	end := 0
+	for i := 0; i < 5; i++ {
+		if end = skipValue(buf, end); end < 0 {
+			t.Fatalf("%d: fail to skip the value", i)
+		}
+	}
+
+	if end != len(buf) {
+		t.Errorf("expect the end %d, but got %d", len(buf), end)
+	}
+
+	if end = skipValue(buf[:len(buf)-1], array); end != -1 {
+		t.Errorf("expect -1 for the incomplete value, but got %d", end)
+	}
+}
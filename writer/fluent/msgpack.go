@@ -0,0 +1,385 @@
+// Copyright 2026 xgfone
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package fluent
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math"
+	"reflect"
+	"time"
+)
+
+// The subset of MessagePack used by the Forward protocol.
+
+func appendNil(dst []byte) []byte { return append(dst, 0xc0) }
+
+func appendBool(dst []byte, b bool) []byte {
+	if b {
+		return append(dst, 0xc3)
+	}
+	return append(dst, 0xc2)
+}
+
+func appendInt(dst []byte, i int64) []byte {
+	switch {
+	case i >= 0:
+		return appendUint(dst, uint64(i))
+	case i >= -32:
+		return append(dst, byte(i))
+	case i >= math.MinInt8:
+		return append(dst, 0xd0, byte(i))
+	case i >= math.MinInt16:
+		return append(dst, 0xd1, byte(i>>8), byte(i))
+	case i >= math.MinInt32:
+		dst = append(dst, 0xd2)
+		return appendUint32(dst, uint32(i))
+	default:
+		dst = append(dst, 0xd3)
+		return appendUint64(dst, uint64(i))
+	}
+}
+
+func appendUint(dst []byte, u uint64) []byte {
+	switch {
+	case u < 128:
+		return append(dst, byte(u))
+	case u <= math.MaxUint8:
+		return append(dst, 0xcc, byte(u))
+	case u <= math.MaxUint16:
+		return append(dst, 0xcd, byte(u>>8), byte(u))
+	case u <= math.MaxUint32:
+		dst = append(dst, 0xce)
+		return appendUint32(dst, uint32(u))
+	default:
+		dst = append(dst, 0xcf)
+		return appendUint64(dst, u)
+	}
+}
+
+func appendFloat(dst []byte, f float64) []byte {
+	dst = append(dst, 0xcb)
+	return appendUint64(dst, math.Float64bits(f))
+}
+
+func appendUint32(dst []byte, u uint32) []byte {
+	return append(dst, byte(u>>24), byte(u>>16), byte(u>>8), byte(u))
+}
+
+func appendUint64(dst []byte, u uint64) []byte {
+	return append(dst, byte(u>>56), byte(u>>48), byte(u>>40), byte(u>>32),
+		byte(u>>24), byte(u>>16), byte(u>>8), byte(u))
+}
+
+func appendString(dst []byte, s string) []byte {
+	switch n := len(s); {
+	case n < 32:
+		dst = append(dst, 0xa0|byte(n))
+	case n <= math.MaxUint8:
+		dst = append(dst, 0xd9, byte(n))
+	case n <= math.MaxUint16:
+		dst = append(dst, 0xda, byte(n>>8), byte(n))
+	default:
+		dst = append(dst, 0xdb)
+		dst = appendUint32(dst, uint32(n))
+	}
+	return append(dst, s...)
+}
+
+func appendBinary(dst []byte, b []byte) []byte {
+	switch n := len(b); {
+	case n <= math.MaxUint8:
+		dst = append(dst, 0xc4, byte(n))
+	case n <= math.MaxUint16:
+		dst = append(dst, 0xc5, byte(n>>8), byte(n))
+	default:
+		dst = append(dst, 0xc6)
+		dst = appendUint32(dst, uint32(n))
+	}
+	return append(dst, b...)
+}
+
+func appendArrayHeader(dst []byte, n int) []byte {
+	switch {
+	case n < 16:
+		return append(dst, 0x90|byte(n))
+	case n <= math.MaxUint16:
+		return append(dst, 0xdc, byte(n>>8), byte(n))
+	default:
+		dst = append(dst, 0xdd)
+		return appendUint32(dst, uint32(n))
+	}
+}
+
+func appendMapHeader(dst []byte, n int) []byte {
+	switch {
+	case n < 16:
+		return append(dst, 0x80|byte(n))
+	case n <= math.MaxUint16:
+		return append(dst, 0xde, byte(n>>8), byte(n))
+	default:
+		dst = append(dst, 0xdf)
+		return appendUint32(dst, uint32(n))
+	}
+}
+
+// appendEventTime appends the time as the EventTime of the Forward protocol,
+// which is the MessagePack extension type 0 with the seconds and nanoseconds.
+func appendEventTime(dst []byte, t time.Time) []byte {
+	dst = append(dst, 0xd7, 0x00)
+	dst = appendUint32(dst, uint32(t.Unix()))
+	return appendUint32(dst, uint32(t.Nanosecond()))
+}
+
+func appendAny(dst []byte, value interface{}) []byte {
+	switch v := value.(type) {
+	case nil:
+		return appendNil(dst)
+	case bool:
+		return appendBool(dst, v)
+	case int:
+		return appendInt(dst, int64(v))
+	case int8:
+		return appendInt(dst, int64(v))
+	case int16:
+		return appendInt(dst, int64(v))
+	case int32:
+		return appendInt(dst, int64(v))
+	case int64:
+		return appendInt(dst, v)
+	case uint:
+		return appendUint(dst, uint64(v))
+	case uint8:
+		return appendUint(dst, uint64(v))
+	case uint16:
+		return appendUint(dst, uint64(v))
+	case uint32:
+		return appendUint(dst, uint64(v))
+	case uint64:
+		return appendUint(dst, v)
+	case float32:
+		return appendFloat(dst, float64(v))
+	case float64:
+		return appendFloat(dst, v)
+	case string:
+		return appendString(dst, v)
+	case []byte:
+		return appendBinary(dst, v)
+	case time.Time:
+		return appendString(dst, v.Format(time.RFC3339Nano))
+	case time.Duration:
+		return appendString(dst, v.String())
+	case error:
+		return appendString(dst, v.Error())
+	case fmt.Stringer:
+		return appendString(dst, v.String())
+	case []string:
+		dst = appendArrayHeader(dst, len(v))
+		for _, s := range v {
+			dst = appendString(dst, s)
+		}
+		return dst
+	case []interface{}:
+		dst = appendArrayHeader(dst, len(v))
+		for _, e := range v {
+			dst = appendAny(dst, e)
+		}
+		return dst
+	case map[string]string:
+		dst = appendMapHeader(dst, len(v))
+		for key, value := range v {
+			dst = appendString(dst, key)
+			dst = appendString(dst, value)
+		}
+		return dst
+	case map[string]interface{}:
+		dst = appendMapHeader(dst, len(v))
+		for key, value := range v {
+			dst = appendString(dst, key)
+			dst = appendAny(dst, value)
+		}
+		return dst
+	default:
+		return appendReflect(dst, reflect.ValueOf(value))
+	}
+}
+
+func appendReflect(dst []byte, v reflect.Value) []byte {
+	switch v.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		if v.IsNil() {
+			return appendNil(dst)
+		}
+		return appendAny(dst, v.Elem().Interface())
+
+	case reflect.Bool:
+		return appendBool(dst, v.Bool())
+
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return appendInt(dst, v.Int())
+
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return appendUint(dst, v.Uint())
+
+	case reflect.Float32, reflect.Float64:
+		return appendFloat(dst, v.Float())
+
+	case reflect.String:
+		return appendString(dst, v.String())
+
+	case reflect.Slice, reflect.Array:
+		if v.Kind() == reflect.Slice && v.IsNil() {
+			return appendNil(dst)
+		}
+
+		_len := v.Len()
+		dst = appendArrayHeader(dst, _len)
+		for i := 0; i < _len; i++ {
+			dst = appendAny(dst, v.Index(i).Interface())
+		}
+		return dst
+
+	case reflect.Map:
+		if v.IsNil() {
+			return appendNil(dst)
+		}
+
+		dst = appendMapHeader(dst, v.Len())
+		for _, key := range v.MapKeys() {
+			dst = appendString(dst, fmt.Sprint(key.Interface()))
+			dst = appendAny(dst, v.MapIndex(key).Interface())
+		}
+		return dst
+
+	default:
+		return appendString(dst, fmt.Sprintf("%+v", v.Interface()))
+	}
+}
+
+/// ----------------------------------------------------------------------- ///
+
+// skipValue returns the position after the MessagePack value starting at i,
+// or -1 if the value is incomplete or invalid.
+func skipValue(b []byte, i int) int {
+	if i < 0 || i >= len(b) {
+		return -1
+	}
+
+	var size, items int // The size of the data and the number of the sub-values
+	c := b[i]
+	i++
+	switch {
+	case c <= 0x7f || c >= 0xe0: // positive and negative fixint
+	case c <= 0x8f: // fixmap
+		items = int(c&0x0f) * 2
+	case c <= 0x9f: // fixarray
+		items = int(c & 0x0f)
+	case c <= 0xbf: // fixstr
+		size = int(c & 0x1f)
+	case c == 0xc0 || c == 0xc2 || c == 0xc3: // nil, false, true
+	case c == 0xc4 || c == 0xd9: // bin8, str8
+		size, i = readLength(b, i, 1)
+	case c == 0xc5 || c == 0xda: // bin16, str16
+		size, i = readLength(b, i, 2)
+	case c == 0xc6 || c == 0xdb: // bin32, str32
+		size, i = readLength(b, i, 4)
+	case c == 0xc7: // ext8
+		size, i = readLength(b, i, 1)
+		size++
+	case c == 0xc8: // ext16
+		size, i = readLength(b, i, 2)
+		size++
+	case c == 0xc9: // ext32
+		size, i = readLength(b, i, 4)
+		size++
+	case c == 0xca: // float32
+		size = 4
+	case c == 0xcb: // float64
+		size = 8
+	case c >= 0xcc && c <= 0xcf: // uint8-64
+		size = 1 << (c - 0xcc)
+	case c >= 0xd0 && c <= 0xd3: // int8-64
+		size = 1 << (c - 0xd0)
+	case c >= 0xd4 && c <= 0xd8: // fixext1-16
+		size = 1<<(c-0xd4) + 1
+	case c == 0xdc: // array16
+		items, i = readLength(b, i, 2)
+	case c == 0xdd: // array32
+		items, i = readLength(b, i, 4)
+	case c == 0xde: // map16
+		items, i = readLength(b, i, 2)
+		items *= 2
+	case c == 0xdf: // map32
+		items, i = readLength(b, i, 4)
+		items *= 2
+	default:
+		return -1
+	}
+
+	if i < 0 || i+size > len(b) {
+		return -1
+	}
+
+	i += size
+	for ; items > 0 && i >= 0; items-- {
+		i = skipValue(b, i)
+	}
+	return i
+}
+
+// readLength reads the big-endian length with n bytes at i,
+// and returns the length and the position after it, or -1 if incomplete.
+func readLength(b []byte, i, n int) (length, next int) {
+	if i+n > len(b) {
+		return 0, -1
+	}
+
+	switch n {
+	case 1:
+		length = int(b[i])
+	case 2:
+		length = int(binary.BigEndian.Uint16(b[i:]))
+	default:
+		length = int(binary.BigEndian.Uint32(b[i:]))
+	}
+	return length, i + n
+}
+
+// readString reads the string at i, and returns the string
+// and the position after it, or -1 if it is not a string.
+func readString(b []byte, i int) (s string, next int) {
+	if i >= len(b) {
+		return "", -1
+	}
+
+	var size int
+	switch c := b[i]; {
+	case c >= 0xa0 && c <= 0xbf:
+		size, next = int(c&0x1f), i+1
+	case c == 0xd9:
+		size, next = readLength(b, i+1, 1)
+	case c == 0xda:
+		size, next = readLength(b, i+1, 2)
+	case c == 0xdb:
+		size, next = readLength(b, i+1, 4)
+	default:
+		return "", -1
+	}
+
+	if next < 0 || next+size > len(b) {
+		return "", -1
+	}
+	return string(b[next : next+size]), next + size
+}
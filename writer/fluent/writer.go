@@ -0,0 +1,476 @@
+// Copyright 2026 xgfone
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package fluent
+
+import (
+	crand "crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"math/rand"
+	"net"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/xgfone/go-log/writer"
+)
+
+var (
+	_ writer.Flusher = &Writer{}
+
+	errClosed         = errors.New("the fluent writer has been closed")
+	errInvalidMessage = errors.New("the record is not the forward message encoded by fluent.Encoder")
+)
+
+// Writer is a thread-safe writer to send the Forward messages encoded by
+// Encoder to fluentd or fluent-bit, which implements the interfaces
+// writer.Flusher and io.Closer.
+//
+// The entries are batched by the tag and sent in the PackedForward mode,
+// that's, "[tag, entries, option]", when the number or the total size
+// of the entries has reached the limit, or after the flush interval.
+//
+// If RequireAck is true, each message carries the option "chunk", and is
+// kept to be resent with the same chunk id until the server acknowledges it,
+// so that the log is delivered at least once.
+//
+// Write only appends the entry into the batch, and the full batches are
+// sent by the background goroutine of the flush timer. So a slow or dead
+// server does not block the log calls, but the messages are dropped when
+// the queue is full. Only Flush and Close send the messages synchronously,
+// which may be blocked by the network up to Timeout for each message.
+//
+// It connects to the server lazily and reconnects with the exponential
+// backoff after failing.
+type Writer struct {
+	// BatchSize is the maximum number of the entries in a batch.
+	//
+	// Default: 100
+	BatchSize int
+
+	// BatchBytes is the maximum total size of the entries in a batch.
+	//
+	// Default: 1048576 (1MB)
+	BatchBytes int
+
+	// FlushInterval is the maximum interval that the entries stay in the batch.
+	// If less than 0, the batch is only sent when full or flushed.
+	//
+	// Default: time.Second
+	FlushInterval time.Duration
+
+	// If true, require the server to acknowledge each message.
+	//
+	// Default: false
+	RequireAck bool
+
+	// Timeout is the timeout to connect to the server, write the message,
+	// and wait for the acknowledgement.
+	//
+	// Default: 5s
+	Timeout time.Duration
+
+	// MinBackoff and MaxBackoff are the minimum and maximum intervals
+	// of writer.Backoff to reconnect to the server after failing.
+	//
+	// Default: 100ms, 30s
+	MinBackoff time.Duration
+	MaxBackoff time.Duration
+
+	// QueueSize is the maximum number of the messages kept to be sent
+	// while failing. If full, the oldest message is dropped.
+	//
+	// Default: 64
+	QueueSize int
+
+	// OnError is called if set when failing to send the messages.
+	//
+	// Default: nil
+	OnError func(err error)
+
+	network string
+	addr    string
+	dropped uint64
+
+	lock    sync.Mutex
+	batches []*batch
+	entries int
+	size    int
+	queue   []message
+	seq     uint64
+	timer   *time.Timer
+	kicked  bool
+	closed  bool
+
+	// The states of the connection, which are only used by the sender.
+	sendLock sync.Mutex
+	conn     net.Conn
+	backoff  writer.Backoff
+	dialAt   time.Time
+	ackbuf   []byte
+}
+
+type batch struct {
+	tag     string
+	entries []byte
+	count   int
+}
+
+type message struct {
+	seq     uint64
+	data    []byte
+	chunk   string
+	entries int
+}
+
+// NewWriter returns a new fluent writer to send the records encoded by Encoder
+// to the server at addr by the network, such as "tcp" and "unix".
+func NewWriter(network, addr string) *Writer {
+	return &Writer{
+		BatchSize:     100,
+		BatchBytes:    1024 * 1024,
+		FlushInterval: time.Second,
+		Timeout:       time.Second * 5,
+		MinBackoff:    time.Millisecond * 100,
+		MaxBackoff:    time.Second * 30,
+		QueueSize:     64,
+
+		network: network,
+		addr:    addr,
+	}
+}
+
+// Dropped returns the number of the entries dropped when the queue is full
+// or when closing the writer.
+func (w *Writer) Dropped() uint64 { return atomic.LoadUint64(&w.dropped) }
+
+// Write implements the interface io.Writer, which appends the forward message
+// encoded by Encoder into the batch of its tag.
+//
+// When the batches are full, they are packed into the queue and sent by
+// the background goroutine, whose error is reported by OnError.
+func (w *Writer) Write(p []byte) (n int, err error) {
+	tag, start := readString(p, 1)
+	if len(p) == 0 || p[0] != 0x93 || start < 0 || skipValue(p, 0) != len(p) {
+		return 0, errInvalidMessage
+	}
+
+	w.lock.Lock()
+	defer w.lock.Unlock()
+
+	if w.closed {
+		return 0, errClosed
+	}
+
+	b := w.getBatch(tag)
+	b.entries = append(b.entries, 0x92) // [time, record]
+	b.entries = append(b.entries, p[start:]...)
+	b.count++
+	w.entries++
+	w.size += len(p) - start + 1
+
+	if w.entries >= w.BatchSize || w.size >= w.BatchBytes {
+		w.packBatches()
+		w.kick()
+	} else if w.entries == 1 {
+		w.startTimer()
+	}
+	return len(p), nil
+}
+
+func (w *Writer) getBatch(tag string) *batch {
+	for _, b := range w.batches {
+		if b.tag == tag {
+			return b
+		}
+	}
+
+	b := &batch{tag: tag}
+	w.batches = append(w.batches, b)
+	return b
+}
+
+func (w *Writer) startTimer() {
+	if w.FlushInterval > 0 && !w.kicked {
+		w.resetTimer(w.FlushInterval)
+	}
+}
+
+// kick triggers the timer at once to send the queue in the background.
+func (w *Writer) kick() {
+	w.kicked = true
+	w.resetTimer(0)
+}
+
+func (w *Writer) resetTimer(d time.Duration) {
+	if w.timer == nil {
+		w.timer = time.AfterFunc(d, w.flushByTimer)
+	} else {
+		w.timer.Reset(d)
+	}
+}
+
+func (w *Writer) flushByTimer() {
+	w.lock.Lock()
+	w.kicked = false
+	closed := w.closed
+	if !closed {
+		w.packBatches()
+	}
+	w.lock.Unlock()
+
+	if !closed {
+		w.sendQueue(false)
+	}
+}
+
+// Flush sends all the batches and the messages kept to be resent.
+func (w *Writer) Flush() (err error) {
+	w.lock.Lock()
+	closed := w.closed
+	if !closed {
+		w.packBatches()
+	}
+	w.lock.Unlock()
+
+	if !closed {
+		err = w.sendQueue(false)
+	}
+	return
+}
+
+// Close sends all the batches and the kept messages in best effort,
+// then closes the connection.
+func (w *Writer) Close() (err error) {
+	w.lock.Lock()
+	if w.closed {
+		w.lock.Unlock()
+		return nil
+	}
+
+	w.closed = true
+	if w.timer != nil {
+		w.timer.Stop()
+	}
+	w.packBatches()
+	w.lock.Unlock()
+
+	w.sendLock.Lock()
+	defer w.sendLock.Unlock()
+
+	w.dialAt = time.Time{} // Try to connect at once.
+	if w.sendQueueLocked(true) != nil {
+		w.lock.Lock()
+		for _, m := range w.queue {
+			atomic.AddUint64(&w.dropped, uint64(m.entries))
+		}
+		w.queue = nil
+		w.lock.Unlock()
+	}
+
+	if w.conn != nil {
+		err = w.conn.Close()
+		w.conn = nil
+	}
+	return
+}
+
+// packBatches packs the batches into the queue, which must be called
+// with the lock.
+func (w *Writer) packBatches() {
+	for _, b := range w.batches {
+		if b.count > 0 {
+			w.enqueue(w.pack(b))
+		}
+	}
+	w.batches = w.batches[:0]
+	w.entries = 0
+	w.size = 0
+}
+
+// sendQueue sends the messages in the queue in order without holding
+// the lock, so the writing is not blocked by the network. If failing,
+// the remaining messages are kept to be resent later.
+func (w *Writer) sendQueue(closing bool) error {
+	w.sendLock.Lock()
+	defer w.sendLock.Unlock()
+	return w.sendQueueLocked(closing)
+}
+
+func (w *Writer) sendQueueLocked(closing bool) (err error) {
+	for {
+		w.lock.Lock()
+		if len(w.queue) == 0 || (w.closed && !closing) {
+			w.lock.Unlock()
+			return
+		}
+		m := w.queue[0]
+		w.lock.Unlock()
+
+		if err = w.send(m); err != nil {
+			w.lock.Lock()
+			if !w.closed {
+				w.startTimer()
+			}
+			w.lock.Unlock()
+			return
+		}
+
+		// The message may have been dropped by enqueue while sending.
+		w.lock.Lock()
+		if len(w.queue) > 0 && w.queue[0].seq == m.seq {
+			w.queue[0] = message{}
+			w.queue = w.queue[1:]
+		}
+		w.lock.Unlock()
+	}
+}
+
+// pack packs the batch into the PackedForward message.
+func (w *Writer) pack(b *batch) (m message) {
+	m.entries = b.count
+	m.data = append(m.data, 0x93) // [tag, entries, option]
+	m.data = appendString(m.data, b.tag)
+	m.data = appendBinary(m.data, b.entries)
+
+	if w.RequireAck {
+		m.chunk = newChunkID()
+		m.data = appendMapHeader(m.data, 2)
+		m.data = appendString(m.data, "size")
+		m.data = appendUint(m.data, uint64(b.count))
+		m.data = appendString(m.data, "chunk")
+		m.data = appendString(m.data, m.chunk)
+	} else {
+		m.data = appendMapHeader(m.data, 1)
+		m.data = appendString(m.data, "size")
+		m.data = appendUint(m.data, uint64(b.count))
+	}
+	return
+}
+
+func (w *Writer) enqueue(m message) {
+	if w.QueueSize > 0 && len(w.queue) >= w.QueueSize {
+		atomic.AddUint64(&w.dropped, uint64(w.queue[0].entries))
+		w.queue[0] = message{}
+		w.queue = w.queue[1:]
+	}
+	w.seq++
+	m.seq = w.seq
+	w.queue = append(w.queue, m)
+}
+
+func (w *Writer) send(m message) (err error) {
+	if err = w.connect(); err != nil {
+		return
+	}
+
+	if w.Timeout > 0 {
+		w.conn.SetDeadline(time.Now().Add(w.Timeout))
+	}
+
+	if _, err = w.conn.Write(m.data); err == nil && m.chunk != "" {
+		var ack string
+		if ack, err = w.readAck(); err == nil && ack != m.chunk {
+			err = fmt.Errorf("expect the ack '%s', but got '%s'", m.chunk, ack)
+		}
+	}
+
+	if err != nil {
+		w.conn.Close()
+		w.conn = nil
+		w.fail(err)
+	}
+	return
+}
+
+// readAck reads the response like {"ack": "<chunk id>"} from the server.
+func (w *Writer) readAck() (ack string, err error) {
+	var buf [256]byte
+	w.ackbuf = w.ackbuf[:0]
+	for {
+		if end := skipValue(w.ackbuf, 0); end > 0 {
+			return parseAck(w.ackbuf[:end])
+		}
+
+		n, err := w.conn.Read(buf[:])
+		if n > 0 {
+			w.ackbuf = append(w.ackbuf, buf[:n]...)
+		} else if err != nil {
+			return "", err
+		}
+	}
+}
+
+func parseAck(resp []byte) (ack string, err error) {
+	var n, i int
+	switch c := resp[0]; {
+	case c >= 0x80 && c <= 0x8f:
+		n, i = int(c&0x0f), 1
+	case c == 0xde:
+		n, i = readLength(resp, 1, 2)
+	case c == 0xdf:
+		n, i = readLength(resp, 1, 4)
+	default:
+		return "", fmt.Errorf("invalid ack response: %x", resp)
+	}
+
+	for ; n > 0 && i > 0; n-- {
+		var key string
+		if key, i = readString(resp, i); key == "ack" && i > 0 {
+			if ack, i = readString(resp, i); i > 0 {
+				return ack, nil
+			}
+		} else {
+			i = skipValue(resp, i)
+		}
+	}
+	return "", fmt.Errorf("invalid ack response: %x", resp)
+}
+
+func (w *Writer) connect() (err error) {
+	if w.conn != nil {
+		return nil
+	}
+
+	if now := time.Now(); now.Before(w.dialAt) {
+		return writer.ErrDisconnected
+	}
+
+	if w.conn, err = net.DialTimeout(w.network, w.addr, w.Timeout); err != nil {
+		w.fail(err)
+		return
+	}
+
+	w.backoff.Reset()
+	return
+}
+
+// fail reports the error and schedules the next connection by the backoff.
+func (w *Writer) fail(err error) {
+	if w.OnError != nil {
+		w.OnError(err)
+	}
+
+	w.dialAt = time.Now().Add(w.backoff.Next(w.MinBackoff, w.MaxBackoff))
+}
+
+func newChunkID() string {
+	var id [16]byte
+	if _, err := crand.Read(id[:]); err != nil {
+		rand.Read(id[:])
+	}
+	return base64.StdEncoding.EncodeToString(id[:])
+}
@@ -0,0 +1,326 @@
+// Copyright 2026 xgfone
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package fluent
+
+import (
+	"net"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/xgfone/go-log"
+)
+
+type forwardMessage struct {
+	tag   string
+	size  int64
+	chunk string
+	msgs  []string
+}
+
+// forwardServer is a fake Forward server only supporting PackedForward.
+type forwardServer struct {
+	ln net.Listener
+
+	lock     sync.Mutex
+	messages []forwardMessage
+	chunks   []string // The chunk ids of all the received messages
+	noAcks   int      // The number of the messages to close the connection without ack
+}
+
+func newForwardServer(t *testing.T) *forwardServer {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	s := &forwardServer{ln: ln}
+	go s.serve()
+	return s
+}
+
+func (s *forwardServer) Addr() string { return s.ln.Addr().String() }
+func (s *forwardServer) Close()       { s.ln.Close() }
+
+func (s *forwardServer) Messages() []forwardMessage {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	return append([]forwardMessage(nil), s.messages...)
+}
+
+func (s *forwardServer) Chunks() []string {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	return append([]string(nil), s.chunks...)
+}
+
+func (s *forwardServer) serve() {
+	for {
+		conn, err := s.ln.Accept()
+		if err != nil {
+			return
+		}
+		go s.handle(conn)
+	}
+}
+
+func (s *forwardServer) handle(conn net.Conn) {
+	defer conn.Close()
+
+	var data []byte
+	var buf [4096]byte
+	for {
+		end := skipValue(data, 0)
+		if end < 0 {
+			n, err := conn.Read(buf[:])
+			if err != nil {
+				return
+			}
+			data = append(data, buf[:n]...)
+			continue
+		}
+
+		v, _ := decode(data, 0)
+		data = data[end:]
+
+		msg := v.([]interface{})
+		entries := msg[1].([]byte)
+		option := msg[2].(map[string]interface{})
+
+		m := forwardMessage{tag: msg[0].(string), size: option["size"].(int64)}
+		for i := 0; i < len(entries); {
+			var entry interface{}
+			entry, i = decode(entries, i)
+			record := entry.([]interface{})[1].(map[string]interface{})
+			m.msgs = append(m.msgs, record["msg"].(string))
+		}
+
+		if chunk, ok := option["chunk"]; ok {
+			m.chunk = chunk.(string)
+		}
+
+		s.lock.Lock()
+		dup := false
+		if m.chunk != "" {
+			for _, chunk := range s.chunks {
+				dup = dup || chunk == m.chunk
+			}
+			s.chunks = append(s.chunks, m.chunk)
+		}
+
+		if !dup {
+			s.messages = append(s.messages, m)
+		}
+
+		noAck := m.chunk != "" && s.noAcks > 0
+		if noAck {
+			s.noAcks--
+		}
+		s.lock.Unlock()
+
+		if m.chunk != "" {
+			if noAck {
+				return
+			}
+
+			ack := appendMapHeader(nil, 1)
+			ack = appendString(ack, "ack")
+			ack = appendString(ack, m.chunk)
+			conn.Write(ack)
+		}
+	}
+}
+
+func waitMessages(s *forwardServer, n int) []forwardMessage {
+	for i := 0; i < 100; i++ {
+		if messages := s.Messages(); len(messages) >= n {
+			return messages
+		}
+		time.Sleep(time.Millisecond * 10)
+	}
+	return s.Messages()
+}
+
+func testMessage(t *testing.T, m forwardMessage, tag string, msgs ...string) {
+	if m.tag != tag {
+		t.Errorf("expect tag '%s', but got '%s'", tag, m.tag)
+	}
+
+	if m.size != int64(len(msgs)) || len(m.msgs) != len(msgs) {
+		t.Errorf("expect %d entries, but got %d/%d", len(msgs), m.size, len(m.msgs))
+		return
+	}
+
+	for i, msg := range msgs {
+		if m.msgs[i] != msg {
+			t.Errorf("expect msg '%s', but got '%s'", msg, m.msgs[i])
+		}
+	}
+}
+
+func TestWriterBatch(t *testing.T) {
+	server := newForwardServer(t)
+	defer server.Close()
+
+	w := NewWriter("tcp", server.Addr())
+	w.BatchSize = 3
+	w.FlushInterval = -1
+	defer w.Close()
+
+	enc := NewEncoder()
+	enc.Tag = "app"
+	logger := log.New("").WithEncoder(enc).WithWriter(w)
+
+	logger.WithName("a").Info().Printf("msg1")
+	logger.WithName("b").Info().Printf("msg2")
+	logger.WithName("a").Info().Printf("msg3")
+	logger.Info().Printf("msg4")
+	if err := w.Flush(); err != nil {
+		t.Fatal(err)
+	}
+
+	messages := waitMessages(server, 3)
+	if len(messages) != 3 {
+		t.Fatalf("expect %d messages, but got %d", 3, len(messages))
+	}
+
+	testMessage(t, messages[0], "app.a", "msg1", "msg3")
+	testMessage(t, messages[1], "app.b", "msg2")
+	testMessage(t, messages[2], "app", "msg4")
+
+	if _, err := w.Write([]byte("invalid")); err == nil {
+		t.Errorf("expect an error, but got nil")
+	}
+}
+
+func TestWriterFlushInterval(t *testing.T) {
+	server := newForwardServer(t)
+	defer server.Close()
+
+	w := NewWriter("tcp", server.Addr())
+	w.FlushInterval = time.Millisecond * 20
+	defer w.Close()
+
+	enc := NewEncoder()
+	enc.Tag = "app"
+	log.New("").WithEncoder(enc).WithWriter(w).Info().Printf("msg")
+
+	if messages := waitMessages(server, 1); len(messages) != 1 {
+		t.Errorf("expect %d messages, but got %d", 1, len(messages))
+	} else {
+		testMessage(t, messages[0], "app", "msg")
+	}
+}
+
+func TestWriterAck(t *testing.T) {
+	server := newForwardServer(t)
+	server.noAcks = 1
+	defer server.Close()
+
+	var errs int
+	w := NewWriter("tcp", server.Addr())
+	w.RequireAck = true
+	w.FlushInterval = -1
+	w.MinBackoff = time.Millisecond
+	w.MaxBackoff = time.Millisecond
+	w.OnError = func(error) { errs++ }
+
+	enc := NewEncoder()
+	enc.Tag = "app"
+	logger := log.New("").WithEncoder(enc).WithWriter(w)
+	logger.Info().Printf("msg1")
+	logger.Info().Printf("msg2")
+
+	// The connection is closed by the server without the ack.
+	if err := w.Flush(); err == nil {
+		t.Errorf("expect an error, but got nil")
+	}
+
+	// Resend the message with the same chunk id after reconnecting.
+	time.Sleep(time.Millisecond * 10)
+	if err := w.Flush(); err != nil {
+		t.Error(err)
+	}
+	w.Close()
+
+	if errs != 1 {
+		t.Errorf("expect %d errors, but got %d", 1, errs)
+	}
+	if n := w.Dropped(); n != 0 {
+		t.Errorf("expect %d dropped entries, but got %d", 0, n)
+	}
+
+	if chunks := server.Chunks(); len(chunks) != 2 || chunks[0] != chunks[1] {
+		t.Errorf("expect the same chunk id to be resent, but got %v", chunks)
+	}
+
+	if messages := server.Messages(); len(messages) != 1 {
+		t.Errorf("expect %d messages, but got %d", 1, len(messages))
+	} else {
+		testMessage(t, messages[0], "app", "msg1", "msg2")
+	}
+}
+
+func TestWriterNotBlocked(t *testing.T) {
+	// The server accepts the connections, but never acknowledges the messages.
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ln.Close()
+
+	var lock sync.Mutex
+	var conns []net.Conn
+	defer func() {
+		lock.Lock()
+		for _, conn := range conns {
+			conn.Close()
+		}
+		lock.Unlock()
+	}()
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			lock.Lock()
+			conns = append(conns, conn)
+			lock.Unlock()
+		}
+	}()
+
+	w := NewWriter("tcp", ln.Addr().String())
+	w.BatchSize = 1
+	w.RequireAck = true
+	w.Timeout = time.Second
+
+	enc := NewEncoder()
+	enc.Tag = "app"
+	logger := log.New("").WithEncoder(enc).WithWriter(w)
+
+	start := time.Now()
+	for i := 0; i < 3; i++ {
+		logger.Info().Printf("msg")
+	}
+	if elapsed := time.Since(start); elapsed > w.Timeout/2 {
+		t.Errorf("the writing is blocked by the network for %s", elapsed)
+	}
+
+	w.Close()
+	if n := w.Dropped(); n != 3 {
+		t.Errorf("expect %d dropped entries, but got %d", 3, n)
+	}
+}
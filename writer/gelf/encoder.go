@@ -0,0 +1,230 @@
+// Copyright 2026 xgfone
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package gelf provides the encoder and writer to send the log record
+// to Graylog by GELF 1.1 over UDP or TCP.
+package gelf
+
+import (
+	"math"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/xgfone/go-log/encoder"
+	"github.com/xgfone/go-log/encoder/kvjson"
+	"github.com/xgfone/go-log/writer/syslog"
+)
+
+var (
+	_ encoder.Int64Encoder       = &Encoder{}
+	_ encoder.Uint64Encoder      = &Encoder{}
+	_ encoder.Float64Encoder     = &Encoder{}
+	_ encoder.BoolEncoder        = &Encoder{}
+	_ encoder.StringEncoder      = &Encoder{}
+	_ encoder.TimeEncoder        = &Encoder{}
+	_ encoder.DurationEncoder    = &Encoder{}
+	_ encoder.StringSliceEncoder = &Encoder{}
+)
+
+// Encoder is a log encoder to encode the log record as the GELF 1.1 message
+// in JSON, which is used with Writer.
+//
+//   - The level is encoded as the syslog severity by syslog.ParseSeverity.
+//   - The message is encoded as short_message, which is "-" if empty.
+//   - The key "full_message" is encoded as full_message.
+//   - The other keys are encoded as the additional fields prefixed with "_",
+//     and the characters except letters, digits, '_', '.' and '-' in the key
+//     are replaced with '_'. But the key "id" is encoded as "__id",
+//     because "_id" is reserved.
+//
+// The value of the additional field is encoded as the number or the string.
+// So the other types, such as the bool, the time and the slice, are
+// formatted as the string, and the nested object is flattened, so the key
+// of the nested field is like "_a.b".
+type Encoder struct {
+	// Host is the host field of the message.
+	//
+	// Default: os.Hostname()
+	Host string
+
+	// LoggerKey is the key of the additional field of the logger name
+	// if not empty.
+	//
+	// Default: "logger"
+	LoggerKey string
+
+	// LevelKey is the key of the additional field of the level string
+	// if not empty, such as "lvl".
+	//
+	// Default: ""
+	LevelKey string
+}
+
+// NewEncoder returns a new GELF encoder.
+func NewEncoder() *Encoder {
+	host, _ := os.Hostname()
+	return &Encoder{Host: host, LoggerKey: "logger"}
+}
+
+// Start implements the interface log.Encoder.
+func (enc *Encoder) Start(buf []byte, name, level string) []byte {
+	buf = append(buf, `{"version":"1.1","host":`...)
+	buf = kvjson.AppendJSONString(buf, enc.Host)
+
+	now := encoder.Now()
+	buf = append(buf, `,"timestamp":`...)
+	buf = strconv.AppendInt(buf, now.Unix(), 10)
+	ms := now.Nanosecond() / int(time.Millisecond)
+	buf = append(buf, '.', byte('0'+ms/100), byte('0'+ms/10%10), byte('0'+ms%10))
+
+	buf = append(buf, `,"level":`...)
+	buf = strconv.AppendInt(buf, int64(syslog.ParseSeverity(level)), 10)
+
+	if enc.LoggerKey != "" && name != "" {
+		buf = enc.EncodeString(buf, enc.LoggerKey, name)
+	}
+	if enc.LevelKey != "" {
+		buf = enc.EncodeString(buf, enc.LevelKey, level)
+	}
+	return buf
+}
+
+// End implements the interface log.Encoder.
+func (enc *Encoder) End(buf []byte, msg string) []byte {
+	if msg == "" {
+		msg = "-"
+	}
+
+	buf = append(buf, `,"short_message":`...)
+	buf = kvjson.AppendJSONString(buf, msg)
+	return append(buf, '}')
+}
+
+// Encode implements the interface log.Encoder.
+func (enc *Encoder) Encode(buf []byte, key string, value interface{}) []byte {
+	switch v := value.(type) {
+	case int:
+		return enc.EncodeInt64(buf, key, int64(v))
+	case int8:
+		return enc.EncodeInt64(buf, key, int64(v))
+	case int16:
+		return enc.EncodeInt64(buf, key, int64(v))
+	case int32:
+		return enc.EncodeInt64(buf, key, int64(v))
+	case int64:
+		return enc.EncodeInt64(buf, key, v)
+	case uint:
+		return enc.EncodeUint64(buf, key, uint64(v))
+	case uint8:
+		return enc.EncodeUint64(buf, key, uint64(v))
+	case uint16:
+		return enc.EncodeUint64(buf, key, uint64(v))
+	case uint32:
+		return enc.EncodeUint64(buf, key, uint64(v))
+	case uint64:
+		return enc.EncodeUint64(buf, key, v)
+	case float32:
+		return enc.EncodeFloat64(buf, key, float64(v))
+	case float64:
+		return enc.EncodeFloat64(buf, key, v)
+	case bool:
+		return enc.EncodeBool(buf, key, v)
+	case string:
+		return enc.EncodeString(buf, key, v)
+	case []string:
+		return enc.EncodeStringSlice(buf, key, v)
+	case time.Time:
+		return enc.EncodeTime(buf, key, v)
+	case time.Duration:
+		return enc.EncodeDuration(buf, key, v)
+	case encoder.ObjectMarshaler:
+		return encoder.EncodeObject(buf, enc, key, v)
+	case encoder.ArrayMarshaler:
+		return encoder.EncodeArray(buf, enc, key, v)
+	default:
+		return enc.EncodeString(buf, key, string(kvjson.JSON{}.EncodeAny(nil, v)))
+	}
+}
+
+// EncodeInt64 implements the interface encoder.Int64Encoder.
+func (enc *Encoder) EncodeInt64(dst []byte, key string, value int64) []byte {
+	return strconv.AppendInt(appendKey(dst, key), value, 10)
+}
+
+// EncodeUint64 implements the interface encoder.Uint64Encoder.
+func (enc *Encoder) EncodeUint64(dst []byte, key string, value uint64) []byte {
+	return strconv.AppendUint(appendKey(dst, key), value, 10)
+}
+
+// EncodeFloat64 implements the interface encoder.Float64Encoder,
+// which encodes NaN and ±Inf as the string.
+func (enc *Encoder) EncodeFloat64(dst []byte, key string, value float64) []byte {
+	if math.IsNaN(value) || math.IsInf(value, 0) {
+		return enc.EncodeString(dst, key, strconv.FormatFloat(value, 'f', -1, 64))
+	}
+	return strconv.AppendFloat(appendKey(dst, key), value, 'f', -1, 64)
+}
+
+// EncodeBool implements the interface encoder.BoolEncoder.
+func (enc *Encoder) EncodeBool(dst []byte, key string, value bool) []byte {
+	return enc.EncodeString(dst, key, strconv.FormatBool(value))
+}
+
+// EncodeString implements the interface encoder.StringEncoder.
+func (enc *Encoder) EncodeString(dst []byte, key string, value string) []byte {
+	return kvjson.AppendJSONString(appendKey(dst, key), value)
+}
+
+// EncodeTime implements the interface encoder.TimeEncoder.
+func (enc *Encoder) EncodeTime(dst []byte, key string, value time.Time) []byte {
+	return enc.EncodeString(dst, key, value.Format(time.RFC3339Nano))
+}
+
+// EncodeDuration implements the interface encoder.DurationEncoder.
+func (enc *Encoder) EncodeDuration(dst []byte, key string, value time.Duration) []byte {
+	return enc.EncodeString(dst, key, value.String())
+}
+
+// EncodeStringSlice implements the interface encoder.StringSliceEncoder,
+// which joins the elements by the newline, such as the call stacks.
+func (enc *Encoder) EncodeStringSlice(dst []byte, key string, value []string) []byte {
+	return enc.EncodeString(dst, key, strings.Join(value, "\n"))
+}
+
+// appendKey appends the key of the additional field with the leading comma.
+func appendKey(dst []byte, key string) []byte {
+	dst = append(dst, ',', '"')
+	if key == "full_message" {
+		dst = append(dst, key...)
+		return append(dst, '"', ':')
+	}
+
+	dst = append(dst, '_')
+	if key == "id" { // The reserved field "_id"
+		dst = append(dst, '_')
+	}
+
+	for i := 0; i < len(key); i++ {
+		switch c := key[i]; {
+		case c >= 'a' && c <= 'z', c >= 'A' && c <= 'Z', c >= '0' && c <= '9',
+			c == '_', c == '.', c == '-':
+			dst = append(dst, c)
+		default:
+			dst = append(dst, '_')
+		}
+	}
+	return append(dst, '"', ':')
+}
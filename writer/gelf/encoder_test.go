@@ -0,0 +1,90 @@
+// Copyright 2026 xgfone
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gelf
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/xgfone/go-log"
+	"github.com/xgfone/go-log/encoder"
+)
+
+type object struct{}
+
+func (object) MarshalLogObject(enc encoder.FieldEncoder) {
+	enc.AddAny("name", "xgfone")
+	enc.AddAny("age", 18)
+}
+
+func TestEncoder(t *testing.T) {
+	defer func(now func() time.Time) { encoder.Now = now }(encoder.Now)
+	encoder.Now = func() time.Time { return time.Unix(1700000000, 5000000) }
+
+	enc := NewEncoder()
+	enc.Host = "localhost"
+	enc.LevelKey = "lvl"
+
+	buf := bytes.NewBuffer(nil)
+	logger := log.New("test").WithWriter(buf).WithEncoder(enc).WithLevel(log.LvlInfo)
+	logger.Error().
+		Int("id", 123).
+		Float64("float", 1.5).
+		Bool("bool", true).
+		Str("a b", "c").
+		Str("full_message", "full\nmessage").
+		StrSlice("stacks", []string{"file1:1", "file2:2"}).
+		Kv("obj", object{}).
+		Kv("nil", nil).
+		Printf("msg")
+
+	var result map[string]interface{}
+	if err := json.Unmarshal(buf.Bytes(), &result); err != nil {
+		t.Fatalf("invalid json '%s': %s", buf.String(), err)
+	}
+
+	expect := map[string]interface{}{
+		"version":       "1.1",
+		"host":          "localhost",
+		"timestamp":     1700000000.005,
+		"level":         float64(3),
+		"short_message": "msg",
+		"full_message":  "full\nmessage",
+		"_logger":       "test",
+		"_lvl":          "error",
+		"__id":          float64(123),
+		"_float":        1.5,
+		"_bool":         "true",
+		"_a_b":          "c",
+		"_stacks":       "file1:1\nfile2:2",
+		"_obj.name":     "xgfone",
+		"_obj.age":      float64(18),
+		"_nil":          "null",
+	}
+
+	if !reflect.DeepEqual(result, expect) {
+		t.Errorf("expect '%v', but got '%v'", expect, result)
+	}
+
+	buf.Reset()
+	log.New("").WithWriter(buf).WithEncoder(enc).Info().Printf("")
+	if s, expect := buf.String(), `{"version":"1.1","host":"localhost",`+
+		`"timestamp":1700000000.005,"level":6,"_lvl":"info","short_message":"-"}`; s != expect {
+		t.Errorf("expect '%s', but got '%s'", expect, s)
+	}
+}
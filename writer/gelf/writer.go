@@ -0,0 +1,221 @@
+// Copyright 2026 xgfone
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gelf
+
+import (
+	"bytes"
+	"compress/gzip"
+	"compress/zlib"
+	crand "crypto/rand"
+	"encoding/binary"
+	"fmt"
+	"math/rand"
+	"net"
+	"sync"
+	"time"
+)
+
+// Compression is the compression of the GELF message sent over UDP.
+type Compression int
+
+// Predefine some compressions.
+const (
+	CompressNone Compression = iota
+	CompressGzip
+	CompressZlib
+)
+
+// The constants of the chunked GELF message.
+const (
+	chunkHeaderSize = 12
+	maxChunks       = 128
+)
+
+// Writer is a thread-safe writer to send the GELF message encoded by Encoder
+// to Graylog over UDP or TCP, which implements io.Closer.
+//
+// Over UDP, the message is compressed if Compression is set, and split into
+// the chunks if it is larger than ChunkSize. Over TCP, the message is not
+// compressed and is terminated by a null byte.
+//
+// It connects to the server lazily when writing the first message,
+// and reconnects and resends the message once if failing to write it.
+type Writer struct {
+	// Compression is the compression of the message sent over UDP.
+	//
+	// Default: CompressNone
+	Compression Compression
+
+	// ChunkSize is the maximum size of the UDP datagram, including
+	// the chunk header. The larger message is split into the chunks,
+	// but it fails if the message needs more than 128 chunks.
+	//
+	// Default: 1420
+	ChunkSize int
+
+	// Timeout is the timeout to connect to the server and write the message.
+	//
+	// Default: 5s
+	Timeout time.Duration
+
+	network string
+	addr    string
+
+	lock  sync.Mutex
+	conn  net.Conn
+	buf   bytes.Buffer
+	chunk []byte
+	gzip  *gzip.Writer
+	zlib  *zlib.Writer
+}
+
+// NewWriter returns a new GELF writer to send the message to the server
+// at addr by the network, which is "udp" or "tcp", or their variants
+// like "udp4".
+func NewWriter(network, addr string) *Writer {
+	return &Writer{
+		ChunkSize: 1420,
+		Timeout:   time.Second * 5,
+
+		network: network,
+		addr:    addr,
+	}
+}
+
+// Close closes the connection to the server.
+func (w *Writer) Close() (err error) {
+	w.lock.Lock()
+	if w.conn != nil {
+		err = w.conn.Close()
+		w.conn = nil
+	}
+	w.lock.Unlock()
+	return
+}
+
+// Write implements the interface io.Writer.
+func (w *Writer) Write(p []byte) (n int, err error) {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+
+	msg := w.encode(bytes.TrimRight(p, "\r\n"))
+	if w.isUDP() && len(msg) > w.ChunkSize {
+		if _, count := w.chunks(len(msg)); count > maxChunks {
+			return 0, fmt.Errorf("the gelf message is too large to be chunked: %d bytes", len(msg))
+		}
+	}
+
+	for i := 0; i < 2; i++ {
+		if w.conn == nil {
+			if w.conn, err = net.DialTimeout(w.network, w.addr, w.Timeout); err != nil {
+				return
+			}
+		}
+
+		if err = w.write(msg); err == nil {
+			return len(p), nil
+		}
+
+		// Reconnect and resend the message.
+		w.conn.Close()
+		w.conn = nil
+	}
+	return
+}
+
+// encode compresses the message over UDP, or appends the null byte over TCP.
+func (w *Writer) encode(p []byte) []byte {
+	w.buf.Reset()
+	if !w.isUDP() {
+		w.buf.Write(p)
+		w.buf.WriteByte(0)
+		return w.buf.Bytes()
+	}
+
+	switch w.Compression {
+	case CompressGzip:
+		if w.gzip == nil {
+			w.gzip = gzip.NewWriter(&w.buf)
+		} else {
+			w.gzip.Reset(&w.buf)
+		}
+		w.gzip.Write(p)
+		w.gzip.Close()
+
+	case CompressZlib:
+		if w.zlib == nil {
+			w.zlib = zlib.NewWriter(&w.buf)
+		} else {
+			w.zlib.Reset(&w.buf)
+		}
+		w.zlib.Write(p)
+		w.zlib.Close()
+
+	default:
+		return p
+	}
+
+	return w.buf.Bytes()
+}
+
+func (w *Writer) write(msg []byte) (err error) {
+	if w.Timeout > 0 {
+		w.conn.SetWriteDeadline(time.Now().Add(w.Timeout))
+	}
+
+	if !w.isUDP() || len(msg) <= w.ChunkSize {
+		_, err = w.conn.Write(msg)
+		return
+	}
+
+	var id [8]byte
+	if _, e := crand.Read(id[:]); e != nil {
+		binary.BigEndian.PutUint64(id[:], uint64(rand.Int63()))
+	}
+
+	size, count := w.chunks(len(msg))
+	for seq := 0; seq < count; seq++ {
+		start, end := seq*size, (seq+1)*size
+		if end > len(msg) {
+			end = len(msg)
+		}
+
+		w.chunk = append(w.chunk[:0], 0x1e, 0x0f) // The magic bytes
+		w.chunk = append(w.chunk, id[:]...)
+		w.chunk = append(w.chunk, byte(seq), byte(count))
+		w.chunk = append(w.chunk, msg[start:end]...)
+		if _, err = w.conn.Write(w.chunk); err != nil {
+			return
+		}
+	}
+	return
+}
+
+// chunks returns the size of the data in each chunk and the number of the chunks.
+func (w *Writer) chunks(n int) (size, count int) {
+	if size = w.ChunkSize - chunkHeaderSize; size <= 0 {
+		size = 1
+	}
+	return size, (n + size - 1) / size
+}
+
+func (w *Writer) isUDP() bool {
+	switch w.network {
+	case "udp", "udp4", "udp6":
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,194 @@
+// Copyright 2026 xgfone
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gelf
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"compress/zlib"
+	"io"
+	"io/ioutil"
+	"net"
+	"strings"
+	"testing"
+	"time"
+)
+
+func listenUDP(t *testing.T) *net.UDPConn {
+	conn, err := net.ListenUDP("udp", &net.UDPAddr{IP: net.IPv4(127, 0, 0, 1)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	conn.SetReadDeadline(time.Now().Add(time.Second * 5))
+	return conn
+}
+
+// readMessage reads a GELF message from the UDP connection,
+// and reassembles the chunks if chunked.
+func readMessage(t *testing.T, conn *net.UDPConn) (msg []byte, chunks int) {
+	var id []byte
+	var parts [][]byte
+	for {
+		buf := make([]byte, 65536)
+		n, err := conn.Read(buf)
+		if err != nil {
+			t.Fatal(err)
+		}
+		buf = buf[:n]
+
+		if n < 2 || buf[0] != 0x1e || buf[1] != 0x0f {
+			return buf, 0
+		}
+
+		if n < 12 {
+			t.Fatalf("invalid chunk: %x", buf)
+		}
+
+		if id == nil {
+			id = buf[2:10]
+			parts = make([][]byte, buf[11])
+		} else if !bytes.Equal(id, buf[2:10]) {
+			t.Fatalf("expect the message id %x, but got %x", id, buf[2:10])
+		}
+
+		parts[buf[10]] = buf[12:]
+		if chunks++; chunks == len(parts) {
+			return bytes.Join(parts, nil), chunks
+		}
+	}
+}
+
+func TestWriterUDP(t *testing.T) {
+	server := listenUDP(t)
+	defer server.Close()
+
+	w := NewWriter("udp", server.LocalAddr().String())
+	w.ChunkSize = 112
+	defer w.Close()
+
+	// Not chunked
+	if _, err := w.Write([]byte(`{"short_message":"msg"}` + "\n")); err != nil {
+		t.Fatal(err)
+	}
+	if msg, chunks := readMessage(t, server); chunks != 0 || string(msg) != `{"short_message":"msg"}` {
+		t.Errorf("unexpected message '%s' in %d chunks", msg, chunks)
+	}
+
+	// Chunked
+	large := `{"short_message":"` + strings.Repeat("x", 1000) + `"}`
+	if _, err := w.Write([]byte(large)); err != nil {
+		t.Fatal(err)
+	}
+	if msg, chunks := readMessage(t, server); chunks != 11 || string(msg) != large {
+		t.Errorf("unexpected message '%s' in %d chunks", msg, chunks)
+	}
+
+	// Too many chunks
+	if _, err := w.Write([]byte(strings.Repeat("x", 100*129))); err == nil {
+		t.Errorf("expect an error, but got nil")
+	}
+}
+
+func TestWriterUDPCompression(t *testing.T) {
+	server := listenUDP(t)
+	defer server.Close()
+
+	w := NewWriter("udp", server.LocalAddr().String())
+	w.ChunkSize = 100
+	defer w.Close()
+
+	large := `{"short_message":"` + strings.Repeat("abcdefghijklmnopqrstuvwxyz", 500) + `"}`
+	for _, compression := range []Compression{CompressGzip, CompressZlib} {
+		w.Compression = compression
+		if _, err := w.Write([]byte(large)); err != nil {
+			t.Fatal(err)
+		}
+
+		msg, chunks := readMessage(t, server)
+		if chunks < 2 {
+			t.Errorf("%d: expect the chunked message, but got %d chunks", compression, chunks)
+		}
+
+		var r io.Reader
+		var err error
+		if compression == CompressGzip {
+			r, err = gzip.NewReader(bytes.NewReader(msg))
+		} else {
+			r, err = zlib.NewReader(bytes.NewReader(msg))
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if data, err := ioutil.ReadAll(r); err != nil {
+			t.Error(err)
+		} else if string(data) != large {
+			t.Errorf("%d: unexpected message '%s'", compression, data)
+		}
+	}
+}
+
+func TestWriterTCP(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ln.Close()
+
+	msgs := make(chan string, 4)
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+
+			go func(conn net.Conn) {
+				defer conn.Close()
+				r := bufio.NewReader(conn)
+				for {
+					msg, err := r.ReadString(0)
+					if err != nil {
+						return
+					}
+					msgs <- msg
+				}
+			}(conn)
+		}
+	}()
+
+	w := NewWriter("tcp", ln.Addr().String())
+	w.Compression = CompressGzip // Ignored by TCP
+	defer w.Close()
+
+	large := `{"short_message":"` + strings.Repeat("x", 2000) + `"}`
+	for _, msg := range []string{`{"short_message":"msg"}` + "\n", large} {
+		if _, err := w.Write([]byte(msg)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	for _, expect := range []string{`{"short_message":"msg"}`, large} {
+		select {
+		case msg := <-msgs:
+			if msg != expect+"\x00" {
+				t.Errorf("expect '%s', but got '%s'", expect, msg)
+			}
+		case <-time.After(time.Second * 5):
+			t.Fatal("timeout")
+		}
+	}
+}
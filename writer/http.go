@@ -0,0 +1,588 @@
+// Copyright 2026 xgfone
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package writer
+
+import (
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"sort"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/xgfone/go-log/encoder/kvjson"
+)
+
+// HTTPRecord is an encoded record to be sent by HTTPLevelWriter.
+type HTTPRecord struct {
+	// Time is the time when the record is written.
+	Time time.Time
+
+	// Level is the level of the record written by WriteLevel, or 0 by Write.
+	Level int
+
+	// Data is the encoded record, such as a JSON line.
+	Data []byte
+}
+
+// HTTPBodyBuilder is used to build the request body from a batch of records.
+type HTTPBodyBuilder interface {
+	// ContentType returns the value of the request header Content-Type.
+	ContentType() string
+
+	// AppendBody appends the request body built from records into dst.
+	AppendBody(dst []byte, records []HTTPRecord) []byte
+}
+
+// trimRecord removes the trailing newline of the encoded record.
+func trimRecord(data []byte) []byte {
+	return bytes.TrimRight(data, "\r\n")
+}
+
+// NDJSONBody builds the body as the newline-delimited JSON,
+// that's, one record per line.
+type NDJSONBody struct{}
+
+// ContentType implements the interface HTTPBodyBuilder.
+func (NDJSONBody) ContentType() string { return "application/x-ndjson" }
+
+// AppendBody implements the interface HTTPBodyBuilder.
+func (NDJSONBody) AppendBody(dst []byte, records []HTTPRecord) []byte {
+	for _, r := range records {
+		dst = append(dst, trimRecord(r.Data)...)
+		dst = append(dst, '\n')
+	}
+	return dst
+}
+
+// JSONArrayBody builds the body as the JSON array of the records,
+// each of which must be a valid JSON value.
+type JSONArrayBody struct{}
+
+// ContentType implements the interface HTTPBodyBuilder.
+func (JSONArrayBody) ContentType() string { return "application/json" }
+
+// AppendBody implements the interface HTTPBodyBuilder.
+func (JSONArrayBody) AppendBody(dst []byte, records []HTTPRecord) []byte {
+	dst = append(dst, '[')
+	for i, r := range records {
+		if i > 0 {
+			dst = append(dst, ',')
+		}
+		dst = append(dst, trimRecord(r.Data)...)
+	}
+	return append(dst, ']')
+}
+
+// ESBulkBody builds the body for the Elasticsearch bulk API "_bulk",
+// which indexes each record, as a JSON document, by the action "index".
+type ESBulkBody struct {
+	// Index is the name of the index into which the records are indexed.
+	// If empty, the index must be given by the url, such as "/myindex/_bulk".
+	//
+	// Default: ""
+	Index string
+}
+
+// ContentType implements the interface HTTPBodyBuilder.
+func (b ESBulkBody) ContentType() string { return "application/x-ndjson" }
+
+// AppendBody implements the interface HTTPBodyBuilder.
+func (b ESBulkBody) AppendBody(dst []byte, records []HTTPRecord) []byte {
+	for _, r := range records {
+		if b.Index == "" {
+			dst = append(dst, `{"index":{}}`...)
+		} else {
+			dst = append(dst, `{"index":{"_index":`...)
+			dst = kvjson.AppendJSONString(dst, b.Index)
+			dst = append(dst, '}', '}')
+		}
+
+		dst = append(dst, '\n')
+		dst = append(dst, trimRecord(r.Data)...)
+		dst = append(dst, '\n')
+	}
+	return dst
+}
+
+// LokiBody builds the body for the Grafana Loki push API "/loki/api/v1/push"
+// in JSON, which pushes all the records as the lines of a stream
+// with the nanosecond timestamps.
+type LokiBody struct {
+	// Labels is the labels of the stream, such as {"app": "myapp"}.
+	//
+	// Default: nil
+	Labels map[string]string
+}
+
+// ContentType implements the interface HTTPBodyBuilder.
+func (b LokiBody) ContentType() string { return "application/json" }
+
+// AppendBody implements the interface HTTPBodyBuilder.
+func (b LokiBody) AppendBody(dst []byte, records []HTTPRecord) []byte {
+	keys := make([]string, 0, len(b.Labels))
+	for key := range b.Labels {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	dst = append(dst, `{"streams":[{"stream":{`...)
+	for i, key := range keys {
+		if i > 0 {
+			dst = append(dst, ',')
+		}
+		dst = kvjson.AppendJSONString(dst, key)
+		dst = append(dst, ':')
+		dst = kvjson.AppendJSONString(dst, b.Labels[key])
+	}
+
+	dst = append(dst, `},"values":[`...)
+	for i, r := range records {
+		if i > 0 {
+			dst = append(dst, ',')
+		}
+		dst = append(dst, '[', '"')
+		dst = strconv.AppendInt(dst, r.Time.UnixNano(), 10)
+		dst = append(dst, '"', ',')
+		dst = kvjson.AppendJSONString(dst, string(trimRecord(r.Data)))
+		dst = append(dst, ']')
+	}
+	return append(dst, `]}]}`...)
+}
+
+/// ----------------------------------------------------------------------- ///
+
+// HTTPError is the error that the server responds the unexpected status code.
+type HTTPError struct {
+	StatusCode int
+	Body       []byte // The leading part of the response body
+}
+
+// Error implements the interface error.
+func (e HTTPError) Error() string {
+	return fmt.Sprintf("the http server responds the status code %d: %s",
+		e.StatusCode, e.Body)
+}
+
+// ErrHTTPClosed is returned when writing the record into the closed HTTPLevelWriter.
+var ErrHTTPClosed = errors.New("the http writer has been closed")
+
+// HTTPOptions is the options of HTTPLevelWriter.
+type HTTPOptions struct {
+	// Method is the method of the request.
+	//
+	// Default: "POST"
+	Method string
+
+	// Header is the additional headers of the request, such as Authorization.
+	//
+	// Default: nil
+	Header http.Header
+
+	// Client is used to send the request.
+	//
+	// Default: &http.Client{Timeout: 10 * time.Second}
+	Client *http.Client
+
+	// Body is used to build the request body from a batch of records.
+	//
+	// Default: NDJSONBody{}
+	Body HTTPBodyBuilder
+
+	// If true, compress the request body by gzip
+	// with the header "Content-Encoding: gzip".
+	//
+	// Default: false
+	Gzip bool
+
+	// MaxCount is the maximum number of the records in a batch.
+	//
+	// Default: 100
+	MaxCount int
+
+	// MaxBytes is the maximum total size of the records in a batch.
+	// But a batch contains at least one record.
+	//
+	// Default: 1048576 (1MB)
+	MaxBytes int
+
+	// MaxLatency is the maximum duration that a record waits in the batch
+	// before the batch is sent, that's, the batch is sent after the duration
+	// since the first record is added into it. If less than 0, disable it.
+	//
+	// Default: time.Second
+	MaxLatency time.Duration
+
+	// MaxPending is the maximum number of the batches waiting to be sent.
+	// If full, the writing is blocked until a batch has been sent.
+	//
+	// Default: 8
+	MaxPending int
+
+	// MaxRetries is the maximum number of the retries to send a batch
+	// after failing by the network error or the status code 429 or 5xx.
+	// If less than 0, never retry.
+	//
+	// Default: 3
+	MaxRetries int
+
+	// MinBackoff and MaxBackoff are the minimum and maximum intervals
+	// of Backoff to retry.
+	//
+	// But the header Retry-After of the response with the status code
+	// 429 or 5xx is preferred if given, which is limited by MaxBackoff.
+	//
+	// Default: 500ms, 30s
+	MinBackoff time.Duration
+	MaxBackoff time.Duration
+
+	// OnFailure is called in the background goroutine if set
+	// when a batch of records is failed to be sent permanently,
+	// that's, the server responds the status code that is not retryable
+	// or the retries are exhausted.
+	//
+	// Default: nil
+	OnFailure func(records []HTTPRecord, err error)
+}
+
+// HTTPLevelWriter is a thread-safe writer to send the records to the http
+// server in batches, which implements the interfaces LevelWriter, Flusher
+// and io.Closer.
+//
+// The records are collected into a batch, which is sent in the background
+// goroutine when the number or the total size of the records has reached
+// the limit, or the first record has waited for the maximum latency.
+//
+// Notice: the batch is handed over with the lock, so if the pending batches
+// are full because the server is slow or unavailable, not only the writing
+// but also Flush and Close are blocked until a pending batch has been sent
+// or given up after the retries.
+type HTTPLevelWriter struct {
+	sent   uint64
+	failed uint64
+
+	url     string
+	options HTTPOptions
+
+	lock    sync.Mutex
+	batch   []HTTPRecord
+	size    int
+	timer   *time.Timer
+	closed  bool
+	batches chan httpBatch
+	stop    chan struct{}
+	stopped sync.Once
+	exit    chan struct{}
+
+	// Only used by the background goroutine
+	body []byte
+	gzip *gzip.Writer
+	gbuf bytes.Buffer
+}
+
+type httpBatch struct {
+	records []HTTPRecord
+	flush   chan error // Only for the flush request
+}
+
+// HTTPWriter returns a new HTTPLevelWriter to send the records to the http
+// server at url in batches, and starts the background goroutine.
+func HTTPWriter(url string, options HTTPOptions) *HTTPLevelWriter {
+	if options.Method == "" {
+		options.Method = http.MethodPost
+	}
+	if options.Client == nil {
+		options.Client = &http.Client{Timeout: time.Second * 10}
+	}
+	if options.Body == nil {
+		options.Body = NDJSONBody{}
+	}
+	if options.MaxCount <= 0 {
+		options.MaxCount = 100
+	}
+	if options.MaxBytes <= 0 {
+		options.MaxBytes = 1024 * 1024
+	}
+	if options.MaxLatency == 0 {
+		options.MaxLatency = time.Second
+	}
+	if options.MaxPending <= 0 {
+		options.MaxPending = 8
+	}
+	if options.MaxRetries == 0 {
+		options.MaxRetries = 3
+	}
+	if options.MinBackoff <= 0 {
+		options.MinBackoff = time.Millisecond * 500
+	}
+	if options.MaxBackoff < options.MinBackoff {
+		options.MaxBackoff = time.Second * 30
+		if options.MaxBackoff < options.MinBackoff {
+			options.MaxBackoff = options.MinBackoff
+		}
+	}
+
+	w := &HTTPLevelWriter{
+		url:     url,
+		options: options,
+		batches: make(chan httpBatch, options.MaxPending),
+		stop:    make(chan struct{}),
+		exit:    make(chan struct{}),
+	}
+	go w.loop()
+	return w
+}
+
+// Sent returns the number of the records that have been sent successfully.
+func (w *HTTPLevelWriter) Sent() uint64 { return atomic.LoadUint64(&w.sent) }
+
+// Failed returns the number of the records that are failed to be sent.
+func (w *HTTPLevelWriter) Failed() uint64 { return atomic.LoadUint64(&w.failed) }
+
+// Write implements the interface io.Writer.
+func (w *HTTPLevelWriter) Write(p []byte) (n int, err error) {
+	return w.WriteLevel(0, p)
+}
+
+// WriteLevel implements the interface LevelWriter.
+func (w *HTTPLevelWriter) WriteLevel(level int, p []byte) (n int, err error) {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+
+	if w.closed {
+		return 0, ErrHTTPClosed
+	}
+
+	if len(w.batch) > 0 && w.size+len(p) > w.options.MaxBytes {
+		w.push(nil)
+	}
+
+	data := append([]byte(nil), p...)
+	w.batch = append(w.batch, HTTPRecord{Time: time.Now(), Level: level, Data: data})
+	w.size += len(p)
+
+	if len(w.batch) >= w.options.MaxCount || w.size >= w.options.MaxBytes {
+		w.push(nil)
+	} else if len(w.batch) == 1 && w.options.MaxLatency > 0 {
+		// Start the timer when the first record is added into the batch.
+		if w.timer == nil {
+			w.timer = time.AfterFunc(w.options.MaxLatency, w.pushByTimer)
+		} else {
+			w.timer.Reset(w.options.MaxLatency)
+		}
+	}
+
+	return len(p), nil
+}
+
+// push hands the current batch over to the background goroutine,
+// which must be called with the lock.
+func (w *HTTPLevelWriter) push(flush chan error) {
+	if len(w.batch) == 0 && flush == nil {
+		return
+	}
+
+	if w.timer != nil {
+		w.timer.Stop()
+	}
+
+	w.batches <- httpBatch{records: w.batch, flush: flush}
+	w.batch = nil
+	w.size = 0
+}
+
+// pushByTimer pushes the batch whose first record has waited for MaxLatency.
+//
+// The timer may fire while the batch is being pushed by the writing, so
+// the new batch after it is not pushed by the stale timer until it is due.
+func (w *HTTPLevelWriter) pushByTimer() {
+	w.lock.Lock()
+	if !w.closed && len(w.batch) > 0 &&
+		time.Since(w.batch[0].Time) >= w.options.MaxLatency {
+		w.push(nil)
+	}
+	w.lock.Unlock()
+}
+
+// Flush sends the current batch and waits until all the batches
+// before it have been sent, then returns the error of the current batch.
+func (w *HTTPLevelWriter) Flush() error {
+	w.lock.Lock()
+	if w.closed {
+		w.lock.Unlock()
+		return nil
+	}
+
+	flush := make(chan error, 1)
+	w.push(flush)
+	w.lock.Unlock()
+	return <-flush
+}
+
+// Close sends the current batch and waits until all the batches
+// have been sent, then stops the background goroutine.
+//
+// While closing, the failed batches are not retried any more.
+func (w *HTTPLevelWriter) Close() error {
+	// Stop the retries without the lock, which may be held by the writing
+	// blocked on the full pending batches until the current batch is done.
+	w.stopped.Do(func() { close(w.stop) })
+
+	w.lock.Lock()
+	if w.closed {
+		w.lock.Unlock()
+		return nil
+	}
+
+	w.closed = true
+	w.push(nil)
+	close(w.batches)
+	w.lock.Unlock()
+
+	<-w.exit
+	return nil
+}
+
+func (w *HTTPLevelWriter) loop() {
+	defer close(w.exit)
+	for batch := range w.batches {
+		var err error
+		if len(batch.records) > 0 {
+			err = w.send(batch.records)
+		}
+
+		if batch.flush != nil {
+			batch.flush <- err
+		}
+	}
+}
+
+// send sends a batch of records with the retries.
+func (w *HTTPLevelWriter) send(records []HTTPRecord) error {
+	body := w.buildBody(records)
+
+	var backoff Backoff
+	for retries := 0; ; retries++ {
+		retryable, wait, err := w.post(body)
+		if err == nil {
+			atomic.AddUint64(&w.sent, uint64(len(records)))
+			return nil
+		}
+
+		if retryable && retries < w.options.MaxRetries {
+			interval := backoff.Next(w.options.MinBackoff, w.options.MaxBackoff)
+			if wait < 0 {
+				wait = interval
+			} else if wait > w.options.MaxBackoff {
+				wait = w.options.MaxBackoff
+			}
+
+			timer := time.NewTimer(wait)
+			select {
+			case <-timer.C:
+				continue
+			case <-w.stop:
+				timer.Stop()
+			}
+		}
+
+		atomic.AddUint64(&w.failed, uint64(len(records)))
+		if w.options.OnFailure != nil {
+			w.options.OnFailure(records, err)
+		}
+		return err
+	}
+}
+
+func (w *HTTPLevelWriter) buildBody(records []HTTPRecord) []byte {
+	w.body = w.options.Body.AppendBody(w.body[:0], records)
+	if !w.options.Gzip {
+		return w.body
+	}
+
+	w.gbuf.Reset()
+	if w.gzip == nil {
+		w.gzip = gzip.NewWriter(&w.gbuf)
+	} else {
+		w.gzip.Reset(&w.gbuf)
+	}
+
+	w.gzip.Write(w.body)
+	w.gzip.Close()
+	return w.gbuf.Bytes()
+}
+
+// post sends the request body once, and returns whether it is retryable
+// and the interval by the header Retry-After if failed, which is -1 if absent.
+func (w *HTTPLevelWriter) post(body []byte) (retryable bool, wait time.Duration, err error) {
+	req, err := http.NewRequest(w.options.Method, w.url, bytes.NewReader(body))
+	if err != nil {
+		return false, -1, err
+	}
+
+	for key, values := range w.options.Header {
+		req.Header[key] = values
+	}
+	req.Header.Set("Content-Type", w.options.Body.ContentType())
+	if w.options.Gzip {
+		req.Header.Set("Content-Encoding", "gzip")
+	}
+
+	resp, err := w.options.Client.Do(req)
+	if err != nil {
+		return true, -1, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		io.Copy(ioutil.Discard, resp.Body)
+		return false, -1, nil
+	}
+
+	rbody, _ := ioutil.ReadAll(io.LimitReader(resp.Body, 512))
+	err = HTTPError{StatusCode: resp.StatusCode, Body: rbody}
+
+	wait = -1
+	if resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500 {
+		retryable = true
+		if value := resp.Header.Get("Retry-After"); value != "" {
+			wait = parseRetryAfter(value)
+		}
+	}
+	return
+}
+
+// parseRetryAfter parses the value of the header Retry-After,
+// which is the delay seconds or the http date, and returns -1 if invalid.
+func parseRetryAfter(value string) time.Duration {
+	if secs, err := strconv.ParseUint(value, 10, 32); err == nil {
+		return time.Duration(secs) * time.Second
+	}
+
+	if t, err := http.ParseTime(value); err == nil {
+		if wait := t.Sub(time.Now()); wait > 0 {
+			return wait
+		}
+		return 0
+	}
+
+	return -1
+}
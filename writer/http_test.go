@@ -0,0 +1,307 @@
+// Copyright 2026 xgfone
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package writer
+
+import (
+	"compress/gzip"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+)
+
+type httpTestServer struct {
+	*httptest.Server
+
+	lock     sync.Mutex
+	bodies   []string
+	statuses []int // The status codes to respond in turn, then 200.
+	header   http.Header
+}
+
+func newHTTPTestServer(statuses ...int) *httpTestServer {
+	s := &httpTestServer{statuses: statuses, header: make(http.Header)}
+	s.Server = httptest.NewServer(http.HandlerFunc(s.handle))
+	return s
+}
+
+func (s *httpTestServer) handle(rw http.ResponseWriter, r *http.Request) {
+	var reader io.Reader = r.Body
+	if r.Header.Get("Content-Encoding") == "gzip" {
+		gr, err := gzip.NewReader(r.Body)
+		if err != nil {
+			rw.WriteHeader(400)
+			return
+		}
+		reader = gr
+	}
+	body, _ := ioutil.ReadAll(reader)
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	s.bodies = append(s.bodies, string(body))
+	if len(s.statuses) > 0 {
+		for key, values := range s.header {
+			rw.Header()[key] = values
+		}
+		rw.WriteHeader(s.statuses[0])
+		s.statuses = s.statuses[1:]
+	}
+}
+
+func (s *httpTestServer) Bodies() []string {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	return append([]string(nil), s.bodies...)
+}
+
+func testHTTPBodies(t *testing.T, prefix string, expects, results []string) {
+	if len(expects) != len(results) {
+		t.Errorf("%s: expect %d requests, but got %d: %q",
+			prefix, len(expects), len(results), results)
+		return
+	}
+
+	for i, body := range expects {
+		if results[i] != body {
+			t.Errorf("%s: %d request: expect '%q', but got '%q'",
+				prefix, i, body, results[i])
+		}
+	}
+}
+
+func TestHTTPWriterBatch(t *testing.T) {
+	server := newHTTPTestServer()
+	defer server.Close()
+
+	w := HTTPWriter(server.URL, HTTPOptions{MaxCount: 3, MaxBytes: 16, Gzip: true})
+	for i := 0; i < 5; i++ {
+		fmt.Fprintf(w, "%d\n", i)
+	}
+	w.Write([]byte("0123456789abcdef\n")) // Exceed MaxBytes
+	w.Write([]byte("6\n"))
+	if err := w.Flush(); err != nil {
+		t.Error(err)
+	}
+	w.Write([]byte("7\n"))
+	w.Close()
+
+	expects := []string{"0\n1\n2\n", "3\n4\n", "0123456789abcdef\n", "6\n", "7\n"}
+	testHTTPBodies(t, "batch", expects, server.Bodies())
+
+	if n := w.Sent(); n != 8 {
+		t.Errorf("expect %d sent records, but got %d", 8, n)
+	}
+
+	if _, err := w.Write([]byte("8\n")); err != ErrHTTPClosed {
+		t.Errorf("expect error '%v', but got '%v'", ErrHTTPClosed, err)
+	}
+}
+
+func TestHTTPWriterLatency(t *testing.T) {
+	server := newHTTPTestServer()
+	defer server.Close()
+
+	w := HTTPWriter(server.URL, HTTPOptions{MaxLatency: time.Millisecond * 50})
+	defer w.Close()
+
+	w.Write([]byte("1\n"))
+	w.Write([]byte("2\n"))
+	time.Sleep(time.Millisecond * 300)
+	testHTTPBodies(t, "latency", []string{"1\n2\n"}, server.Bodies())
+}
+
+func TestHTTPWriterStaleTimer(t *testing.T) {
+	server := newHTTPTestServer()
+	defer server.Close()
+
+	w := HTTPWriter(server.URL, HTTPOptions{MaxLatency: time.Hour})
+	defer w.Close()
+
+	w.Write([]byte("1\n"))
+	w.pushByTimer() // The timer of the last batch fires late.
+	w.Write([]byte("2\n"))
+	if err := w.Flush(); err != nil {
+		t.Error(err)
+	}
+	testHTTPBodies(t, "stale timer", []string{"1\n2\n"}, server.Bodies())
+}
+
+func TestHTTPWriterRetry(t *testing.T) {
+	server := newHTTPTestServer(503, 429)
+	server.header.Set("Retry-After", "1")
+	defer server.Close()
+
+	var failed bool
+	w := HTTPWriter(server.URL, HTTPOptions{
+		MinBackoff: time.Millisecond,
+		OnFailure:  func([]HTTPRecord, error) { failed = true },
+	})
+
+	start := time.Now()
+	w.Write([]byte("1\n"))
+	if err := w.Flush(); err != nil {
+		t.Error(err)
+	}
+	w.Close()
+
+	if elapsed := time.Since(start); elapsed < time.Second*2 {
+		t.Errorf("expect to wait by Retry-After for 2s, but got %s", elapsed)
+	}
+	if failed {
+		t.Errorf("unexpect to fail")
+	}
+	testHTTPBodies(t, "retry", []string{"1\n", "1\n", "1\n"}, server.Bodies())
+}
+
+func TestHTTPWriterRetryAfterLimit(t *testing.T) {
+	server := newHTTPTestServer(503)
+	server.header.Set("Retry-After", "86400")
+	defer server.Close()
+
+	w := HTTPWriter(server.URL, HTTPOptions{
+		MinBackoff: time.Millisecond,
+		MaxBackoff: time.Millisecond * 10,
+	})
+	defer w.Close()
+
+	start := time.Now()
+	w.Write([]byte("1\n"))
+	if err := w.Flush(); err != nil {
+		t.Error(err)
+	}
+
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("expect to wait for MaxBackoff, but got %s", elapsed)
+	}
+	testHTTPBodies(t, "retry-after", []string{"1\n", "1\n"}, server.Bodies())
+}
+
+func TestHTTPWriterCloseBlocked(t *testing.T) {
+	server := newHTTPTestServer(503, 503, 503, 503)
+	defer server.Close()
+
+	w := HTTPWriter(server.URL, HTTPOptions{
+		MaxCount:   1,
+		MaxPending: 1,
+		MinBackoff: time.Hour,
+		MaxBackoff: time.Hour,
+	})
+
+	// The first batch is waiting for the retry, the second is pending,
+	// and the third is blocked on the full pending batches with the lock.
+	w.Write([]byte("1\n"))
+	w.Write([]byte("2\n"))
+	go w.Write([]byte("3\n"))
+	time.Sleep(time.Millisecond * 50)
+
+	closed := make(chan struct{})
+	go func() { w.Close(); close(closed) }()
+
+	select {
+	case <-closed:
+	case <-time.After(time.Second * 5):
+		t.Fatal("timeout to close the writer")
+	}
+}
+
+func TestHTTPWriterFailure(t *testing.T) {
+	server := newHTTPTestServer(500, 502, 503, 400)
+	defer server.Close()
+
+	var records [][]HTTPRecord
+	var errs []error
+	w := HTTPWriter(server.URL, HTTPOptions{
+		MaxRetries: 2,
+		MinBackoff: time.Millisecond,
+		OnFailure: func(r []HTTPRecord, err error) {
+			records = append(records, r)
+			errs = append(errs, err)
+		},
+	})
+
+	// 500, 502, 503: the retries are exhausted.
+	w.Write([]byte("1\n"))
+	if err, ok := w.Flush().(HTTPError); !ok || err.StatusCode != 503 {
+		t.Errorf("expect the status code 503, but got '%v'", err)
+	}
+
+	// 400: not retryable.
+	w.WriteLevel(40, []byte("2\n"))
+	if err, ok := w.Flush().(HTTPError); !ok || err.StatusCode != 400 {
+		t.Errorf("expect the status code 400, but got '%v'", err)
+	}
+	w.Close()
+
+	testHTTPBodies(t, "failure", []string{"1\n", "1\n", "1\n", "2\n"}, server.Bodies())
+	if n := w.Failed(); n != 2 {
+		t.Errorf("expect %d failed records, but got %d", 2, n)
+	}
+
+	if len(records) != 2 || len(errs) != 2 {
+		t.Fatalf("expect 2 failed batches, but got %d", len(records))
+	}
+	if len(records[1]) != 1 || records[1][0].Level != 40 || string(records[1][0].Data) != "2\n" {
+		t.Errorf("unexpected failed records: %+v", records[1])
+	}
+}
+
+func TestHTTPBodyBuilder(t *testing.T) {
+	records := []HTTPRecord{
+		{Time: time.Unix(1, 0), Data: []byte(`{"msg":"a"}` + "\n")},
+		{Time: time.Unix(2, 0), Data: []byte(`{"msg":"b"}`)},
+	}
+
+	builders := []HTTPBodyBuilder{
+		NDJSONBody{},
+		JSONArrayBody{},
+		ESBulkBody{},
+		ESBulkBody{Index: "logs"},
+		LokiBody{Labels: map[string]string{"job": "test", "app": "go"}},
+	}
+
+	expects := []string{
+		`{"msg":"a"}` + "\n" + `{"msg":"b"}` + "\n",
+		`[{"msg":"a"},{"msg":"b"}]`,
+		`{"index":{}}` + "\n" + `{"msg":"a"}` + "\n" + `{"index":{}}` + "\n" + `{"msg":"b"}` + "\n",
+		`{"index":{"_index":"logs"}}` + "\n" + `{"msg":"a"}` + "\n" +
+			`{"index":{"_index":"logs"}}` + "\n" + `{"msg":"b"}` + "\n",
+		`{"streams":[{"stream":{"app":"go","job":"test"},"values":[` +
+			`["1000000000","{\"msg\":\"a\"}"],["2000000000","{\"msg\":\"b\"}"]]}]}`,
+	}
+
+	for i, builder := range builders {
+		if body := string(builder.AppendBody(nil, records)); body != expects[i] {
+			t.Errorf("%T: expect '%s', but got '%s'", builder, expects[i], body)
+		}
+	}
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	if wait := parseRetryAfter("3"); wait != time.Second*3 {
+		t.Errorf("expect %s, but got %s", time.Second*3, wait)
+	}
+	if wait := parseRetryAfter("Mon, 02 Jan 2006 15:04:05 GMT"); wait != 0 {
+		t.Errorf("expect 0, but got %s", wait)
+	}
+	if wait := parseRetryAfter("abc"); wait != -1 {
+		t.Errorf("expect -1, but got %s", wait)
+	}
+}
@@ -0,0 +1,278 @@
+// Copyright 2026 xgfone
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package journald provides the encoder and writer to send the log record
+// to systemd-journald by its native protocol.
+package journald
+
+import (
+	"encoding/binary"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/xgfone/go-log/encoder"
+	"github.com/xgfone/go-log/encoder/kvjson"
+	"github.com/xgfone/go-log/writer/syslog"
+)
+
+var (
+	_ encoder.Int64Encoder       = &Encoder{}
+	_ encoder.Uint64Encoder      = &Encoder{}
+	_ encoder.Float64Encoder     = &Encoder{}
+	_ encoder.BoolEncoder        = &Encoder{}
+	_ encoder.StringEncoder      = &Encoder{}
+	_ encoder.TimeEncoder        = &Encoder{}
+	_ encoder.DurationEncoder    = &Encoder{}
+	_ encoder.StringSliceEncoder = &Encoder{}
+)
+
+// Encoder is a log encoder to encode the log record as the journal fields
+// of the native protocol of systemd-journald, which is used with Writer.
+//
+//   - The level is encoded as PRIORITY by syslog.ParseSeverity.
+//   - The logger name is encoded as SYSLOG_IDENTIFIER.
+//   - The caller formatted like "file:func:line" by the hook log.Caller
+//     is encoded as CODE_FILE, CODE_FUNC and CODE_LINE.
+//   - The message is encoded as MESSAGE.
+//
+// The other keys are converted to the journal field names, that's,
+// the letters are converted to uppercase, the characters except A-Z, 0-9
+// and '_' are replaced with '_', the leading '_' are removed, and the name
+// is prefixed with "F" if it begins with a digit. The nested object is
+// flattened, so the key "a.b" is converted to "A_B". And the string slice
+// is encoded as the multiple fields with the same name.
+type Encoder struct {
+	// Identifier is used as SYSLOG_IDENTIFIER if the logger name is empty.
+	//
+	// Default: filepath.Base(os.Args[0])
+	Identifier string
+
+	// LevelKey is the journal field name of the level string if not empty,
+	// such as "LEVEL".
+	//
+	// Default: ""
+	LevelKey string
+
+	// CallerKey is the key of the caller added by the hook log.Caller.
+	//
+	// Default: "caller"
+	CallerKey string
+}
+
+// NewEncoder returns a new journald encoder.
+func NewEncoder() *Encoder {
+	return &Encoder{
+		Identifier: filepath.Base(os.Args[0]),
+		CallerKey:  "caller",
+	}
+}
+
+// Start implements the interface log.Encoder.
+func (enc *Encoder) Start(buf []byte, name, level string) []byte {
+	buf = append(buf, "PRIORITY="...)
+	buf = strconv.AppendInt(buf, int64(syslog.ParseSeverity(level)), 10)
+	buf = append(buf, '\n')
+
+	if name == "" {
+		name = enc.Identifier
+	}
+	if name != "" {
+		buf = appendField(buf, "SYSLOG_IDENTIFIER", name)
+	}
+
+	if enc.LevelKey != "" {
+		buf = appendField(buf, enc.LevelKey, level)
+	}
+	return buf
+}
+
+// End implements the interface log.Encoder.
+func (enc *Encoder) End(buf []byte, msg string) []byte {
+	return appendField(buf, "MESSAGE", msg)
+}
+
+// Encode implements the interface log.Encoder.
+func (enc *Encoder) Encode(buf []byte, key string, value interface{}) []byte {
+	switch v := value.(type) {
+	case nil:
+		return enc.EncodeString(buf, key, "null")
+	case bool:
+		return enc.EncodeBool(buf, key, v)
+	case int:
+		return enc.EncodeInt64(buf, key, int64(v))
+	case int8:
+		return enc.EncodeInt64(buf, key, int64(v))
+	case int16:
+		return enc.EncodeInt64(buf, key, int64(v))
+	case int32:
+		return enc.EncodeInt64(buf, key, int64(v))
+	case int64:
+		return enc.EncodeInt64(buf, key, v)
+	case uint:
+		return enc.EncodeUint64(buf, key, uint64(v))
+	case uint8:
+		return enc.EncodeUint64(buf, key, uint64(v))
+	case uint16:
+		return enc.EncodeUint64(buf, key, uint64(v))
+	case uint32:
+		return enc.EncodeUint64(buf, key, uint64(v))
+	case uint64:
+		return enc.EncodeUint64(buf, key, v)
+	case float32:
+		return enc.EncodeString(buf, key, strconv.FormatFloat(float64(v), 'f', -1, 32))
+	case float64:
+		return enc.EncodeFloat64(buf, key, v)
+	case string:
+		return enc.EncodeString(buf, key, v)
+	case []byte:
+		return appendField(buf, fieldName(key), string(v))
+	case []string:
+		return enc.EncodeStringSlice(buf, key, v)
+	case time.Time:
+		return enc.EncodeTime(buf, key, v)
+	case time.Duration:
+		return enc.EncodeDuration(buf, key, v)
+	case encoder.ObjectMarshaler:
+		return encoder.EncodeObject(buf, enc, key, v)
+	case encoder.ArrayMarshaler:
+		return encoder.EncodeArray(buf, enc, key, v)
+	case error:
+		return enc.EncodeString(buf, key, v.Error())
+	case fmt.Stringer:
+		return enc.EncodeString(buf, key, v.String())
+	default:
+		return enc.EncodeString(buf, key, string(kvjson.JSON{}.EncodeAny(nil, v)))
+	}
+}
+
+// EncodeInt64 implements the interface encoder.Int64Encoder.
+func (enc *Encoder) EncodeInt64(dst []byte, key string, value int64) []byte {
+	return enc.EncodeString(dst, key, strconv.FormatInt(value, 10))
+}
+
+// EncodeUint64 implements the interface encoder.Uint64Encoder.
+func (enc *Encoder) EncodeUint64(dst []byte, key string, value uint64) []byte {
+	return enc.EncodeString(dst, key, strconv.FormatUint(value, 10))
+}
+
+// EncodeFloat64 implements the interface encoder.Float64Encoder.
+func (enc *Encoder) EncodeFloat64(dst []byte, key string, value float64) []byte {
+	return enc.EncodeString(dst, key, strconv.FormatFloat(value, 'f', -1, 64))
+}
+
+// EncodeBool implements the interface encoder.BoolEncoder.
+func (enc *Encoder) EncodeBool(dst []byte, key string, value bool) []byte {
+	return enc.EncodeString(dst, key, strconv.FormatBool(value))
+}
+
+// EncodeTime implements the interface encoder.TimeEncoder.
+func (enc *Encoder) EncodeTime(dst []byte, key string, value time.Time) []byte {
+	return enc.EncodeString(dst, key, value.Format(time.RFC3339Nano))
+}
+
+// EncodeDuration implements the interface encoder.DurationEncoder.
+func (enc *Encoder) EncodeDuration(dst []byte, key string, value time.Duration) []byte {
+	return enc.EncodeString(dst, key, value.String())
+}
+
+// EncodeStringSlice implements the interface encoder.StringSliceEncoder,
+// which encodes each element as the field with the same name.
+func (enc *Encoder) EncodeStringSlice(dst []byte, key string, value []string) []byte {
+	name := fieldName(key)
+	for _, s := range value {
+		dst = appendField(dst, name, s)
+	}
+	return dst
+}
+
+// EncodeString implements the interface encoder.StringEncoder.
+func (enc *Encoder) EncodeString(dst []byte, key string, value string) []byte {
+	if key == enc.CallerKey && key != "" {
+		if file, fn, line, ok := parseCaller(value); ok {
+			dst = appendField(dst, "CODE_FILE", file)
+			dst = appendField(dst, "CODE_FUNC", fn)
+			return appendField(dst, "CODE_LINE", line)
+		}
+	}
+	return appendField(dst, fieldName(key), value)
+}
+
+// appendField appends the journal field. If the value contains the newline,
+// it is serialized as the binary data, that's, the name followed by
+// a newline, the little-endian 64-bit length and the value.
+func appendField(dst []byte, name, value string) []byte {
+	dst = append(dst, name...)
+	if strings.IndexByte(value, '\n') < 0 {
+		dst = append(dst, '=')
+	} else {
+		var size [8]byte
+		binary.LittleEndian.PutUint64(size[:], uint64(len(value)))
+		dst = append(dst, '\n')
+		dst = append(dst, size[:]...)
+	}
+	dst = append(dst, value...)
+	return append(dst, '\n')
+}
+
+// fieldName converts the key to the valid journal field name.
+func fieldName(key string) string {
+	key = strings.TrimLeft(key, "_.")
+	if key == "" {
+		return "F"
+	}
+
+	name := make([]byte, 0, len(key)+1)
+	if key[0] >= '0' && key[0] <= '9' {
+		name = append(name, 'F')
+	}
+
+	for i := 0; i < len(key); i++ {
+		switch c := key[i]; {
+		case c >= 'a' && c <= 'z':
+			name = append(name, c-'a'+'A')
+		case c >= 'A' && c <= 'Z', c >= '0' && c <= '9', c == '_':
+			name = append(name, c)
+		default:
+			name = append(name, '_')
+		}
+	}
+
+	if len(name) > 64 {
+		name = name[:64]
+	}
+	return string(name)
+}
+
+// parseCaller parses the caller formatted like "file:func:line".
+func parseCaller(caller string) (file, fn, line string, ok bool) {
+	index := strings.LastIndexByte(caller, ':')
+	if index < 0 {
+		return
+	}
+
+	line = caller[index+1:]
+	if _, err := strconv.Atoi(line); err != nil {
+		return
+	}
+
+	caller = caller[:index]
+	if index = strings.LastIndexByte(caller, ':'); index < 0 {
+		return
+	}
+	return caller[:index], caller[index+1:], line, true
+}
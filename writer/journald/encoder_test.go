@@ -0,0 +1,87 @@
+// Copyright 2026 xgfone
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package journald
+
+import (
+	"bytes"
+	"encoding/binary"
+	"reflect"
+	"testing"
+
+	"github.com/xgfone/go-log"
+	"github.com/xgfone/go-log/encoder"
+)
+
+// parseFields parses the journal fields serialized by the native protocol.
+func parseFields(t *testing.T, data []byte) map[string][]string {
+	fields := make(map[string][]string)
+	for len(data) > 0 {
+		index := bytes.IndexAny(data, "=\n")
+		if index < 0 {
+			t.Fatalf("invalid field '%s'", data)
+		}
+
+		var value []byte
+		name := string(data[:index])
+		if data[index] == '=' {
+			data = data[index+1:]
+			end := bytes.IndexByte(data, '\n')
+			value, data = data[:end], data[end+1:]
+		} else {
+			data = data[index+1:]
+			size := int(binary.LittleEndian.Uint64(data[:8]))
+			value, data = data[8:8+size], data[9+size:]
+		}
+		fields[name] = append(fields[name], string(value))
+	}
+	return fields
+}
+
+type testObject struct{}
+
+func (testObject) MarshalLogObject(enc encoder.FieldEncoder) {
+	enc.AddString("name", "xgfone")
+	enc.AddInt64("age", 18)
+}
+
+func TestEncoder(t *testing.T) {
+	enc := NewEncoder()
+	enc.LevelKey = "LEVEL"
+
+	buf := bytes.NewBuffer(nil)
+	logger := log.New("app").WithWriter(buf).WithEncoder(enc).
+		WithHooks(log.Caller("caller")).WithLevel(log.LvlTrace)
+
+	logger.Level(log.LvlAlert, 0).Kv("key1", 123).Kv("_key.2", "a\nb").
+		Kv("3key", []string{"v1", "v2"}).Kv("obj", testObject{}).Printf("msg")
+
+	expect := map[string][]string{
+		"PRIORITY":          {"1"},
+		"SYSLOG_IDENTIFIER": {"app"},
+		"LEVEL":             {"alert"},
+		"CODE_FILE":         {"encoder_test.go"},
+		"CODE_FUNC":         {"TestEncoder"},
+		"CODE_LINE":         {"67"},
+		"KEY1":              {"123"},
+		"KEY_2":             {"a\nb"},
+		"F3KEY":             {"v1", "v2"},
+		"OBJ_NAME":          {"xgfone"},
+		"OBJ_AGE":           {"18"},
+		"MESSAGE":           {"msg"},
+	}
+	if fields := parseFields(t, buf.Bytes()); !reflect.DeepEqual(fields, expect) {
+		t.Errorf("expect %v, but got %v", expect, fields)
+	}
+}
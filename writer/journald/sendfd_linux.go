@@ -0,0 +1,55 @@
+// Copyright 2026 xgfone
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package journald
+
+import (
+	"io/ioutil"
+	"net"
+	"os"
+	"syscall"
+)
+
+// sendFile writes the data into a unlinked temporary file, and sends
+// its file descriptor to journald, which is used for the large record.
+//
+// memfd_create is preferred by journald, but it is not provided
+// by the package syscall on all the architectures.
+func sendFile(conn *net.UnixConn, addr *net.UnixAddr, data []byte) (err error) {
+	file, err := ioutil.TempFile("/dev/shm", "journald")
+	if err != nil {
+		if file, err = ioutil.TempFile("", "journald"); err != nil {
+			return
+		}
+	}
+	defer file.Close()
+	os.Remove(file.Name())
+
+	if _, err = file.Write(data); err != nil {
+		return
+	}
+
+	_, _, err = conn.WriteMsgUnix(nil, syscall.UnixRights(int(file.Fd())), addr)
+	return
+}
+
+func isTooLarge(err error) bool {
+	if oe, ok := err.(*net.OpError); ok {
+		err = oe.Err
+	}
+	if se, ok := err.(*os.SyscallError); ok {
+		err = se.Err
+	}
+	return err == syscall.EMSGSIZE || err == syscall.ENOBUFS
+}
@@ -0,0 +1,87 @@
+// Copyright 2026 xgfone
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package journald
+
+import (
+	"net"
+	"sync"
+)
+
+// DefaultAddr is the default address of the native protocol socket of journald.
+const DefaultAddr = "/run/systemd/journal/socket"
+
+// Writer is a thread-safe writer to send the log record encoded by Encoder
+// to systemd-journald by the native protocol, which implements io.Closer.
+//
+// The record is sent as a datagram. If it is too large, it is written into
+// a temporary file, such as in "/dev/shm", which is unlinked immediately,
+// then its file descriptor is sent instead, which is only supported on Linux.
+type Writer struct {
+	// Addr is the address of the unix datagram socket of journald.
+	//
+	// Default: DefaultAddr
+	Addr string
+
+	// MaxDatagramSize is the maximum size of the record sent as a datagram.
+	// If greater than 0, the larger record is sent by the file descriptor
+	// directly. Or, try to send it as a datagram firstly.
+	//
+	// Default: 0
+	MaxDatagramSize int
+
+	lock sync.Mutex
+	conn *net.UnixConn
+}
+
+// NewWriter returns a new journald writer.
+func NewWriter() *Writer { return &Writer{Addr: DefaultAddr} }
+
+// Close closes the connection to journald.
+func (w *Writer) Close() (err error) {
+	w.lock.Lock()
+	if w.conn != nil {
+		err = w.conn.Close()
+		w.conn = nil
+	}
+	w.lock.Unlock()
+	return
+}
+
+// Write implements the interface io.Writer, which sends a record
+// encoded by Encoder to journald.
+func (w *Writer) Write(p []byte) (n int, err error) {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+
+	// Use the unconnected socket to send the file descriptor by WriteMsgUnix.
+	if w.conn == nil {
+		laddr := &net.UnixAddr{Net: "unixgram"}
+		if w.conn, err = net.ListenUnixgram("unixgram", laddr); err != nil {
+			return
+		}
+	}
+
+	addr := &net.UnixAddr{Net: "unixgram", Name: w.Addr}
+	if w.MaxDatagramSize <= 0 || len(p) <= w.MaxDatagramSize {
+		if _, err = w.conn.WriteToUnix(p, addr); err == nil || !isTooLarge(err) {
+			return len(p), err
+		}
+	}
+
+	if err = sendFile(w.conn, addr, p); err != nil {
+		return
+	}
+	return len(p), nil
+}
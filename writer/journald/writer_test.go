@@ -0,0 +1,102 @@
+// Copyright 2026 xgfone
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build linux
+// +build linux
+
+package journald
+
+import (
+	"io/ioutil"
+	"net"
+	"os"
+	"path/filepath"
+	"reflect"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/xgfone/go-log"
+)
+
+func TestWriter(t *testing.T) {
+	dir, err := ioutil.TempDir("", "journald")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	addr := filepath.Join(dir, "socket")
+	conn, err := net.ListenUnixgram("unixgram", &net.UnixAddr{Net: "unixgram", Name: addr})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	w := NewWriter()
+	w.Addr = addr
+	w.MaxDatagramSize = 64
+	defer w.Close()
+
+	enc := NewEncoder()
+	enc.Identifier = ""
+	logger := log.New("").WithWriter(w).WithEncoder(enc)
+
+	read := func() map[string][]string {
+		buf := make([]byte, 4096)
+		oob := make([]byte, 64)
+		conn.SetReadDeadline(time.Now().Add(time.Second))
+		n, oobn, _, _, err := conn.ReadMsgUnix(buf, oob)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if oobn == 0 {
+			return parseFields(t, buf[:n])
+		}
+
+		// Read the record from the file descriptor.
+		msgs, err := syscall.ParseSocketControlMessage(oob[:oobn])
+		if err != nil {
+			t.Fatal(err)
+		}
+		fds, err := syscall.ParseUnixRights(&msgs[0])
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		file := os.NewFile(uintptr(fds[0]), "journald")
+		defer file.Close()
+
+		file.Seek(0, 0)
+		data, err := ioutil.ReadAll(file)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return parseFields(t, data)
+	}
+
+	logger.Info().Kv("key", "value").Printf("small")
+	expect := map[string][]string{"PRIORITY": {"6"}, "KEY": {"value"}, "MESSAGE": {"small"}}
+	if fields := read(); !reflect.DeepEqual(fields, expect) {
+		t.Errorf("expect %v, but got %v", expect, fields)
+	}
+
+	large := string(make([]byte, 100))
+	logger.Error().Kv("key", large).Printf("large")
+	expect = map[string][]string{"PRIORITY": {"3"}, "KEY": {large}, "MESSAGE": {"large"}}
+	if fields := read(); !reflect.DeepEqual(fields, expect) {
+		t.Errorf("expect %v, but got %v", expect, fields)
+	}
+}
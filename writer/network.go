@@ -0,0 +1,344 @@
+// Copyright 2026 xgfone
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package writer
+
+import (
+	"encoding/binary"
+	"errors"
+	"net"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Framing is the way to frame the records over the network.
+type Framing int
+
+// Predefine some framings.
+const (
+	// FramingNewline terminates the record with a newline if absent.
+	FramingNewline Framing = iota
+
+	// FramingOctetCounting prefixes the record with its length
+	// in decimal and a space, such as "11 hello world", like RFC 6587.
+	FramingOctetCounting
+
+	// FramingLengthPrefix prefixes the record with its length
+	// as a 4-byte big-endian unsigned integer.
+	FramingLengthPrefix
+)
+
+// NetState is the connection state of NetLevelWriter.
+type NetState int32
+
+// Predefine some connection states.
+const (
+	NetDisconnected NetState = iota
+	NetConnected
+	NetClosed
+)
+
+// String returns the string of the state.
+func (s NetState) String() string {
+	switch s {
+	case NetDisconnected:
+		return "disconnected"
+	case NetConnected:
+		return "connected"
+	case NetClosed:
+		return "closed"
+	default:
+		return "NetState(" + strconv.Itoa(int(s)) + ")"
+	}
+}
+
+// ErrDisconnected is returned when writing the record into NetLevelWriter
+// that is disconnected and has no queue.
+var ErrDisconnected = errors.New("the network writer is disconnected")
+
+// NetOptions is the options of NetLevelWriter.
+type NetOptions struct {
+	// Framing is the way to frame the records.
+	//
+	// Default: FramingNewline
+	Framing Framing
+
+	// DialTimeout is the timeout to connect to the server.
+	//
+	// Default: 5s
+	DialTimeout time.Duration
+
+	// WriteTimeout is the timeout to write a record.
+	//
+	// Default: 5s
+	WriteTimeout time.Duration
+
+	// MinBackoff and MaxBackoff are the minimum and maximum intervals
+	// of Backoff to reconnect to the server after failing.
+	//
+	// Default: 100ms, 30s
+	MinBackoff time.Duration
+	MaxBackoff time.Duration
+
+	// QueueSize is the maximum number of the records buffered in memory
+	// while disconnected, which are sent in order after reconnecting.
+	// If the queue is full, the oldest record is dropped.
+	//
+	// If equal to or less than 0, the record is dropped and ErrDisconnected
+	// is returned while disconnected.
+	//
+	// Default: 0
+	QueueSize int
+
+	// OnError is called when failing to connect to the server
+	// or write the record if set, which is called after releasing
+	// the writer lock, so it may write the error back to the writer.
+	//
+	// Default: nil
+	OnError func(err error)
+}
+
+// NetLevelWriter is a thread-safe writer to write the records to the server
+// over the network, which implements the interfaces LevelWriter, Flusher
+// and io.Closer.
+//
+// It connects to the server lazily when writing the record or flushing,
+// and reconnects with the exponential backoff after failing. So it may be
+// blocked by the network, and can be wrapped by AsyncWriter if necessary.
+type NetLevelWriter struct {
+	errors  uint64
+	dropped uint64
+	state   int32
+
+	network string
+	addr    string
+	options NetOptions
+
+	lock    sync.Mutex
+	conn    net.Conn
+	buf     []byte
+	queue   [][]byte
+	backoff Backoff
+	dialAt  time.Time
+	errs    []error // The errors to be reported by OnError after unlocking.
+}
+
+// NetWriter returns a new NetLevelWriter to write the records to the server
+// at addr by the network, such as "tcp", "udp", "unix" and "unixgram".
+func NetWriter(network, addr string, options NetOptions) *NetLevelWriter {
+	if options.DialTimeout <= 0 {
+		options.DialTimeout = time.Second * 5
+	}
+	if options.WriteTimeout <= 0 {
+		options.WriteTimeout = time.Second * 5
+	}
+	if options.MinBackoff <= 0 {
+		options.MinBackoff = time.Millisecond * 100
+	}
+	if options.MaxBackoff < options.MinBackoff {
+		options.MaxBackoff = time.Second * 30
+		if options.MaxBackoff < options.MinBackoff {
+			options.MaxBackoff = options.MinBackoff
+		}
+	}
+
+	return &NetLevelWriter{network: network, addr: addr, options: options}
+}
+
+// State returns the connection state.
+func (w *NetLevelWriter) State() NetState { return NetState(atomic.LoadInt32(&w.state)) }
+
+// Errors returns the number of the errors to connect to the server
+// or write the records.
+func (w *NetLevelWriter) Errors() uint64 { return atomic.LoadUint64(&w.errors) }
+
+// Dropped returns the number of the dropped records.
+func (w *NetLevelWriter) Dropped() uint64 { return atomic.LoadUint64(&w.dropped) }
+
+// Queued returns the number of the records buffered in the queue.
+func (w *NetLevelWriter) Queued() (n int) {
+	w.lock.Lock()
+	n = len(w.queue)
+	w.lock.Unlock()
+	return
+}
+
+// WriteLevel implements the interface LevelWriter, which ignores the level.
+func (w *NetLevelWriter) WriteLevel(level int, p []byte) (int, error) {
+	return w.Write(p)
+}
+
+// Write implements the interface io.Writer.
+func (w *NetLevelWriter) Write(p []byte) (n int, err error) {
+	w.lock.Lock()
+	defer w.unlock()
+
+	if w.State() == NetClosed {
+		return 0, errors.New("the network writer has been closed")
+	}
+
+	if err = w.flush(); err == nil {
+		if err = w.send(p); err == nil {
+			return len(p), nil
+		}
+	}
+
+	if w.options.QueueSize <= 0 {
+		atomic.AddUint64(&w.dropped, 1)
+		return 0, err
+	}
+
+	if len(w.queue) >= w.options.QueueSize {
+		w.queue[0] = nil
+		w.queue = w.queue[1:]
+		atomic.AddUint64(&w.dropped, 1)
+	}
+	w.queue = append(w.queue, append([]byte(nil), p...))
+	return len(p), nil
+}
+
+// Flush sends the records in the queue if connected or reconnected.
+func (w *NetLevelWriter) Flush() (err error) {
+	w.lock.Lock()
+	if w.State() != NetClosed {
+		err = w.flush()
+	}
+	w.unlock()
+	return
+}
+
+// Close sends the records in the queue in best effort,
+// then closes the connection.
+func (w *NetLevelWriter) Close() (err error) {
+	w.lock.Lock()
+	defer w.unlock()
+
+	if w.State() == NetClosed {
+		return nil
+	}
+
+	if len(w.queue) > 0 {
+		w.dialAt = time.Time{} // Try to connect at once.
+		if w.flush() != nil {
+			atomic.AddUint64(&w.dropped, uint64(len(w.queue)))
+		}
+		w.queue = nil
+	}
+
+	if w.conn != nil {
+		err = w.conn.Close()
+		w.conn = nil
+	}
+	atomic.StoreInt32(&w.state, int32(NetClosed))
+	return
+}
+
+// unlock releases the lock, then reports the errors recorded by fail.
+func (w *NetLevelWriter) unlock() {
+	errs := w.errs
+	w.errs = nil
+	w.lock.Unlock()
+
+	if w.options.OnError != nil {
+		for _, err := range errs {
+			w.options.OnError(err)
+		}
+	}
+}
+
+// flush connects to the server if necessary, and sends the queued records.
+func (w *NetLevelWriter) flush() (err error) {
+	if err = w.connect(); err != nil {
+		return
+	}
+
+	for len(w.queue) > 0 {
+		if err = w.send(w.queue[0]); err != nil {
+			return
+		}
+		w.queue[0] = nil
+		w.queue = w.queue[1:]
+	}
+	return
+}
+
+func (w *NetLevelWriter) connect() (err error) {
+	if w.conn != nil {
+		return nil
+	}
+
+	if now := time.Now(); now.Before(w.dialAt) {
+		return ErrDisconnected
+	}
+
+	w.conn, err = net.DialTimeout(w.network, w.addr, w.options.DialTimeout)
+	if err != nil {
+		w.fail(err)
+		return
+	}
+
+	w.backoff.Reset()
+	atomic.StoreInt32(&w.state, int32(NetConnected))
+	return
+}
+
+func (w *NetLevelWriter) send(p []byte) (err error) {
+	if err = w.connect(); err != nil {
+		return
+	}
+
+	w.buf = w.frame(w.buf[:0], p)
+	w.conn.SetWriteDeadline(time.Now().Add(w.options.WriteTimeout))
+	if _, err = w.conn.Write(w.buf); err != nil {
+		w.conn.Close()
+		w.conn = nil
+		atomic.StoreInt32(&w.state, int32(NetDisconnected))
+		w.fail(err)
+	}
+	return
+}
+
+func (w *NetLevelWriter) frame(dst, p []byte) []byte {
+	switch w.options.Framing {
+	case FramingOctetCounting:
+		dst = strconv.AppendInt(dst, int64(len(p)), 10)
+		dst = append(dst, ' ')
+		return append(dst, p...)
+
+	case FramingLengthPrefix:
+		var size [4]byte
+		binary.BigEndian.PutUint32(size[:], uint32(len(p)))
+		dst = append(dst, size[:]...)
+		return append(dst, p...)
+
+	default:
+		dst = append(dst, p...)
+		if len(p) == 0 || p[len(p)-1] != '\n' {
+			dst = append(dst, '\n')
+		}
+		return dst
+	}
+}
+
+// fail records the error and schedules the next connection by the backoff.
+func (w *NetLevelWriter) fail(err error) {
+	atomic.AddUint64(&w.errors, 1)
+	if w.options.OnError != nil {
+		w.errs = append(w.errs, err)
+	}
+
+	w.dialAt = time.Now().Add(w.backoff.Next(w.options.MinBackoff, w.options.MaxBackoff))
+}
@@ -0,0 +1,226 @@
+// Copyright 2026 xgfone
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package writer
+
+import (
+	"bufio"
+	"encoding/binary"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+// readFrames reads the records framed by framing from the connections
+// accepted by ln, and sends them into the channel.
+func readFrames(ln net.Listener, framing Framing) <-chan string {
+	records := make(chan string, 16)
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+
+			go func(conn net.Conn) {
+				defer conn.Close()
+				r := bufio.NewReader(conn)
+				for {
+					var record string
+					switch framing {
+					case FramingOctetCounting:
+						length, err := r.ReadString(' ')
+						if err != nil {
+							return
+						}
+						n, _ := strconv.Atoi(strings.TrimSpace(length))
+						buf := make([]byte, n)
+						if _, err = io.ReadFull(r, buf); err != nil {
+							return
+						}
+						record = string(buf)
+
+					case FramingLengthPrefix:
+						var size [4]byte
+						if _, err := io.ReadFull(r, size[:]); err != nil {
+							return
+						}
+						buf := make([]byte, binary.BigEndian.Uint32(size[:]))
+						if _, err := io.ReadFull(r, buf); err != nil {
+							return
+						}
+						record = string(buf)
+
+					default:
+						line, err := r.ReadString('\n')
+						if err != nil {
+							return
+						}
+						record = line
+					}
+					records <- record
+				}
+			}(conn)
+		}
+	}()
+	return records
+}
+
+func recvRecord(t *testing.T, records <-chan string) string {
+	select {
+	case record := <-records:
+		return record
+	case <-time.After(time.Second * 5):
+		t.Fatal("timeout to wait for the record")
+		return ""
+	}
+}
+
+func TestNetWriterFraming(t *testing.T) {
+	for _, framing := range []Framing{FramingNewline, FramingOctetCounting, FramingLengthPrefix} {
+		ln, err := net.Listen("tcp", "127.0.0.1:0")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		records := readFrames(ln, framing)
+		w := NetWriter("tcp", ln.Addr().String(), NetOptions{Framing: framing})
+		w.WriteLevel(40, []byte("abc"))
+		w.Write([]byte("a b\n"))
+
+		expects := []string{"abc", "a b\n"}
+		if framing == FramingNewline {
+			expects[0] = "abc\n"
+		}
+		for _, expect := range expects {
+			if record := recvRecord(t, records); record != expect {
+				t.Errorf("framing %d: expect '%s', but got '%s'", framing, expect, record)
+			}
+		}
+
+		if state := w.State(); state != NetConnected {
+			t.Errorf("expect state '%s', but got '%s'", NetConnected, state)
+		}
+
+		Close(w)
+		ln.Close()
+		if state := w.State(); state != NetClosed {
+			t.Errorf("expect state '%s', but got '%s'", NetClosed, state)
+		}
+	}
+}
+
+func TestNetWriterReconnect(t *testing.T) {
+	// Reserve an address without the listener.
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	addr := ln.Addr().String()
+	ln.Close()
+
+	w := NetWriter("tcp", addr, NetOptions{
+		MinBackoff: time.Millisecond,
+		MaxBackoff: time.Millisecond * 10,
+		QueueSize:  3,
+	})
+	defer w.Close()
+
+	for i := 0; i < 5; i++ {
+		if _, err := w.Write([]byte(strconv.Itoa(i))); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if state := w.State(); state != NetDisconnected {
+		t.Errorf("expect state '%s', but got '%s'", NetDisconnected, state)
+	}
+	if n := w.Errors(); n == 0 {
+		t.Errorf("expect some errors, but got 0")
+	}
+	if n := w.Dropped(); n != 2 {
+		t.Errorf("expect %d dropped records, but got %d", 2, n)
+	}
+	if n := w.Queued(); n != 3 {
+		t.Errorf("expect %d queued records, but got %d", 3, n)
+	}
+
+	if ln, err = net.Listen("tcp", addr); err != nil {
+		t.Skipf("failed to listen on %s again: %s", addr, err)
+	}
+	defer ln.Close()
+	records := readFrames(ln, FramingNewline)
+
+	time.Sleep(time.Millisecond * 20) // Wait for the backoff.
+	if err := Flush(w); err != nil {
+		t.Fatal(err)
+	}
+
+	for i := 2; i < 5; i++ {
+		if record, expect := recvRecord(t, records), strconv.Itoa(i)+"\n"; record != expect {
+			t.Errorf("expect '%s', but got '%s'", expect, record)
+		}
+	}
+
+	if n := w.Queued(); n != 0 {
+		t.Errorf("expect no queued records, but got %d", n)
+	}
+	if state := w.State(); state != NetConnected {
+		t.Errorf("expect state '%s', but got '%s'", NetConnected, state)
+	}
+}
+
+func TestNetWriterOnError(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	addr := ln.Addr().String()
+	ln.Close()
+
+	var w *NetLevelWriter
+	var errs []error
+	w = NetWriter("tcp", addr, NetOptions{
+		MinBackoff: time.Minute,
+		QueueSize:  3,
+		OnError: func(err error) {
+			errs = append(errs, err)
+			w.Write([]byte(err.Error())) // Write the error back to the writer.
+		},
+	})
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		w.Write([]byte("msg"))
+		w.Close()
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second * 5):
+		t.Fatal("the writer is deadlocked by OnError")
+	}
+
+	// One is to write the record, and the other is to flush it when closing.
+	if len(errs) != 2 {
+		t.Errorf("expect %d errors, but got %d", 2, len(errs))
+	}
+	if n := w.Dropped(); n != 2 {
+		t.Errorf("expect %d dropped records, but got %d", 2, n)
+	}
+}
@@ -0,0 +1,388 @@
+// Copyright 2026 xgfone
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package otlp provides the encoder and writer to export the log record
+// to the OpenTelemetry collector by OTLP/HTTP in JSON, without the OTel SDK.
+package otlp
+
+import (
+	"bytes"
+	"encoding/hex"
+	"fmt"
+	"math"
+	"strconv"
+	"time"
+
+	"github.com/xgfone/go-log"
+	"github.com/xgfone/go-log/encoder"
+	"github.com/xgfone/go-log/encoder/kvjson"
+)
+
+var (
+	_ encoder.Int64Encoder       = &Encoder{}
+	_ encoder.Uint64Encoder      = &Encoder{}
+	_ encoder.Float64Encoder     = &Encoder{}
+	_ encoder.BoolEncoder        = &Encoder{}
+	_ encoder.StringEncoder      = &Encoder{}
+	_ encoder.TimeEncoder        = &Encoder{}
+	_ encoder.DurationEncoder    = &Encoder{}
+	_ encoder.StringSliceEncoder = &Encoder{}
+)
+
+// Severity is the severity number of the OpenTelemetry log record.
+type Severity int
+
+// Predefine the severity numbers, each of which has 4 sub-levels,
+// such as Info, Info+1, Info+2 and Info+3.
+const (
+	Trace Severity = 1
+	Debug Severity = 5
+	Info  Severity = 9
+	Warn  Severity = 13
+	Error Severity = 17
+	Fatal Severity = 21
+)
+
+// LevelToSeverity converts the level of go-log to the severity number.
+//
+//	[LvlTrace, LvlAlert) => [Trace, Error+3], 5 levels per sub-level
+//	[LvlAlert, LvlPanic) => Error+3
+//	[LvlPanic, LvlFatal) => [Fatal, Fatal+2], 2 levels per sub-level
+//	[LvlFatal, ...)      => Fatal+3
+func LevelToSeverity(level int) Severity {
+	switch {
+	case level < log.LvlTrace:
+		return Trace
+	case level < log.LvlAlert:
+		return Trace + Severity(level/5)
+	case level < log.LvlPanic:
+		return Error + 3
+	case level < log.LvlFatal:
+		return Fatal + Severity((level-log.LvlPanic)/2)
+	default:
+		return Fatal + 3
+	}
+}
+
+// ParseSeverity parses the level string formatted by log.FormatLevel,
+// such as "info" and "info5", by log.ParseFormattedLevel, and converts it
+// to the severity number by LevelToSeverity. The unknown level is parsed as log.LvlInfo.
+func ParseSeverity(level string) Severity {
+	return LevelToSeverity(log.ParseFormattedLevel(level, log.LvlInfo))
+}
+
+/// ----------------------------------------------------------------------- ///
+
+var attributesStart = []byte(`,"attributes":[`)
+
+// Encoder is a log encoder to encode the log record as the LogRecord
+// of the OpenTelemetry logs data model in the OTLP JSON, which is used
+// with Body to build the ExportLogsServiceRequest.
+//
+//   - The level is encoded as severityNumber by ParseSeverity,
+//     and as severityText as it is, such as "info" and "info5".
+//   - The message is encoded as body.
+//   - The value of TraceIDKey or SpanIDKey is encoded as traceId or spanId
+//     if it is the valid id in hex, such as the string or [16]byte.
+//     Or, it is encoded as an attribute like the other key-values.
+//
+// The value of the attribute is encoded as the string, int, double or bool
+// value, or the array value for []string. So the other types are formatted
+// as the string, and the nested object is flattened, so the key of the nested
+// field is like "a.b".
+type Encoder struct {
+	// LoggerKey is the key of the attribute of the logger name if not empty.
+	//
+	// Default: "logger"
+	LoggerKey string
+
+	// TraceIDKey is the key whose value is used as the trace id if not empty.
+	//
+	// Default: "trace_id"
+	TraceIDKey string
+
+	// SpanIDKey is the key whose value is used as the span id if not empty.
+	//
+	// Default: "span_id"
+	SpanIDKey string
+}
+
+// NewEncoder returns a new OTLP JSON encoder.
+func NewEncoder() *Encoder {
+	return &Encoder{LoggerKey: "logger", TraceIDKey: "trace_id", SpanIDKey: "span_id"}
+}
+
+// Start implements the interface log.Encoder.
+func (enc *Encoder) Start(buf []byte, name, level string) []byte {
+	now := strconv.FormatInt(encoder.Now().UnixNano(), 10)
+	buf = append(buf, `{"timeUnixNano":"`...)
+	buf = append(buf, now...)
+	buf = append(buf, `","observedTimeUnixNano":"`...)
+	buf = append(buf, now...)
+	buf = append(buf, `","severityNumber":`...)
+	buf = strconv.AppendInt(buf, int64(ParseSeverity(level)), 10)
+	buf = append(buf, `,"severityText":`...)
+	buf = kvjson.AppendJSONString(buf, level)
+
+	// Each attribute is encoded with the leading comma, because the contexts
+	// are encoded in advance, so the comma after '[' is removed by End.
+	buf = append(buf, attributesStart...)
+	if enc.LoggerKey != "" && name != "" {
+		buf = enc.EncodeString(buf, enc.LoggerKey, name)
+	}
+	return buf
+}
+
+// End implements the interface log.Encoder.
+func (enc *Encoder) End(buf []byte, msg string) []byte {
+	start := bytes.LastIndex(buf, attributesStart) + len(attributesStart)
+	if start < len(buf) && buf[start] == ',' {
+		buf = append(buf[:start], buf[start+1:]...)
+	}
+
+	var traceID, spanID []byte
+	buf, traceID = extractID(buf, start, enc.TraceIDKey, 32)
+	buf, spanID = extractID(buf, start, enc.SpanIDKey, 16)
+	buf = append(buf, ']')
+
+	if len(traceID) > 0 {
+		buf = append(buf, `,"traceId":"`...)
+		buf = append(buf, traceID...)
+		buf = append(buf, '"')
+	}
+	if len(spanID) > 0 {
+		buf = append(buf, `,"spanId":"`...)
+		buf = append(buf, spanID...)
+		buf = append(buf, '"')
+	}
+
+	buf = append(buf, `,"body":{"stringValue":`...)
+	buf = kvjson.AppendJSONString(buf, msg)
+	return append(buf, '}', '}')
+}
+
+// extractID removes the string attributes with the key from the attributes
+// starting at start, and returns the lower value of the last one which is
+// the valid id with size hex characters.
+//
+// The leading `{"key":` only matches the attribute, not inside the string,
+// because the double quotation mark in the string is always escaped.
+func extractID(buf []byte, start int, key string, size int) ([]byte, []byte) {
+	if key == "" {
+		return buf, nil
+	}
+
+	prefix := append([]byte(`{"key":`), kvjson.AppendJSONString(nil, key)...)
+	prefix = append(prefix, `,"value":{"stringValue":"`...)
+
+	var id []byte
+	for i := start; ; {
+		index := bytes.Index(buf[i:], prefix)
+		if index < 0 {
+			return buf, id
+		}
+		index += i
+
+		// Find the end of the string value.
+		end := index + len(prefix)
+		for ; end < len(buf) && buf[end] != '"'; end++ {
+			if buf[end] == '\\' {
+				end++
+			}
+		}
+
+		if end+3 > len(buf) {
+			return buf, id
+		}
+
+		value := buf[index+len(prefix) : end]
+		if end += 3; !isValidID(value, size) { // Skip `"}}`
+			i = end
+			continue
+		}
+		id = bytes.ToLower(value)
+
+		// Remove the attribute with the comma.
+		if end < len(buf) && buf[end] == ',' {
+			end++
+		} else if index > start && buf[index-1] == ',' {
+			index--
+		}
+		buf = append(buf[:index], buf[end:]...)
+		i = index
+	}
+}
+
+func isValidID(id []byte, size int) bool {
+	if len(id) != size {
+		return false
+	}
+
+	var nonzero bool
+	for _, c := range id {
+		switch {
+		case c == '0':
+		case c >= '1' && c <= '9', c >= 'a' && c <= 'f', c >= 'A' && c <= 'F':
+			nonzero = true
+		default:
+			return false
+		}
+	}
+	return nonzero
+}
+
+// Encode implements the interface log.Encoder.
+func (enc *Encoder) Encode(buf []byte, key string, value interface{}) []byte {
+	if key != "" && (key == enc.TraceIDKey || key == enc.SpanIDKey) {
+		switch v := value.(type) {
+		case string:
+			return enc.EncodeString(buf, key, v)
+		case []byte:
+			return enc.EncodeString(buf, key, hex.EncodeToString(v))
+		case [16]byte:
+			return enc.EncodeString(buf, key, hex.EncodeToString(v[:]))
+		case [8]byte:
+			return enc.EncodeString(buf, key, hex.EncodeToString(v[:]))
+		case fmt.Stringer:
+			return enc.EncodeString(buf, key, v.String())
+		}
+	}
+
+	switch v := value.(type) {
+	case nil:
+		return append(appendKey(buf, key), `{}}`...)
+	case int:
+		return enc.EncodeInt64(buf, key, int64(v))
+	case int8:
+		return enc.EncodeInt64(buf, key, int64(v))
+	case int16:
+		return enc.EncodeInt64(buf, key, int64(v))
+	case int32:
+		return enc.EncodeInt64(buf, key, int64(v))
+	case int64:
+		return enc.EncodeInt64(buf, key, v)
+	case uint:
+		return enc.EncodeUint64(buf, key, uint64(v))
+	case uint8:
+		return enc.EncodeUint64(buf, key, uint64(v))
+	case uint16:
+		return enc.EncodeUint64(buf, key, uint64(v))
+	case uint32:
+		return enc.EncodeUint64(buf, key, uint64(v))
+	case uint64:
+		return enc.EncodeUint64(buf, key, v)
+	case float32:
+		return enc.EncodeFloat64(buf, key, float64(v))
+	case float64:
+		return enc.EncodeFloat64(buf, key, v)
+	case bool:
+		return enc.EncodeBool(buf, key, v)
+	case string:
+		return enc.EncodeString(buf, key, v)
+	case []string:
+		return enc.EncodeStringSlice(buf, key, v)
+	case time.Time:
+		return enc.EncodeTime(buf, key, v)
+	case time.Duration:
+		return enc.EncodeDuration(buf, key, v)
+	case encoder.ObjectMarshaler:
+		return encoder.EncodeObject(buf, enc, key, v)
+	case encoder.ArrayMarshaler:
+		return encoder.EncodeArray(buf, enc, key, v)
+	default:
+		return enc.EncodeString(buf, key, string(kvjson.JSON{}.EncodeAny(nil, v)))
+	}
+}
+
+// EncodeInt64 implements the interface encoder.Int64Encoder.
+func (enc *Encoder) EncodeInt64(dst []byte, key string, value int64) []byte {
+	dst = append(appendKey(dst, key), `{"intValue":"`...)
+	dst = strconv.AppendInt(dst, value, 10)
+	return append(dst, `"}}`...)
+}
+
+// EncodeUint64 implements the interface encoder.Uint64Encoder,
+// which encodes the value overflowing int64 as the string.
+func (enc *Encoder) EncodeUint64(dst []byte, key string, value uint64) []byte {
+	if value > math.MaxInt64 {
+		return enc.EncodeString(dst, key, strconv.FormatUint(value, 10))
+	}
+	return enc.EncodeInt64(dst, key, int64(value))
+}
+
+// EncodeFloat64 implements the interface encoder.Float64Encoder,
+// which encodes NaN and ±Inf as "NaN" and "±Infinity" like protobuf.
+func (enc *Encoder) EncodeFloat64(dst []byte, key string, value float64) []byte {
+	dst = append(appendKey(dst, key), `{"doubleValue":`...)
+	switch {
+	case math.IsNaN(value):
+		dst = append(dst, `"NaN"`...)
+	case math.IsInf(value, 1):
+		dst = append(dst, `"Infinity"`...)
+	case math.IsInf(value, -1):
+		dst = append(dst, `"-Infinity"`...)
+	default:
+		dst = strconv.AppendFloat(dst, value, 'g', -1, 64)
+	}
+	return append(dst, '}', '}')
+}
+
+// EncodeBool implements the interface encoder.BoolEncoder.
+func (enc *Encoder) EncodeBool(dst []byte, key string, value bool) []byte {
+	dst = append(appendKey(dst, key), `{"boolValue":`...)
+	dst = strconv.AppendBool(dst, value)
+	return append(dst, '}', '}')
+}
+
+// EncodeString implements the interface encoder.StringEncoder.
+func (enc *Encoder) EncodeString(dst []byte, key string, value string) []byte {
+	return append(appendStringValue(appendKey(dst, key), value), '}')
+}
+
+// EncodeTime implements the interface encoder.TimeEncoder.
+func (enc *Encoder) EncodeTime(dst []byte, key string, value time.Time) []byte {
+	return enc.EncodeString(dst, key, value.Format(time.RFC3339Nano))
+}
+
+// EncodeDuration implements the interface encoder.DurationEncoder.
+func (enc *Encoder) EncodeDuration(dst []byte, key string, value time.Duration) []byte {
+	return enc.EncodeString(dst, key, value.String())
+}
+
+// EncodeStringSlice implements the interface encoder.StringSliceEncoder,
+// which encodes the value as the array value.
+func (enc *Encoder) EncodeStringSlice(dst []byte, key string, value []string) []byte {
+	dst = append(appendKey(dst, key), `{"arrayValue":{"values":[`...)
+	for i, s := range value {
+		if i > 0 {
+			dst = append(dst, ',')
+		}
+		dst = appendStringValue(dst, s)
+	}
+	return append(dst, `]}}}`...)
+}
+
+// appendKey appends the beginning of the attribute with the leading comma,
+// that's, `,{"key":"KEY","value":`.
+func appendKey(dst []byte, key string) []byte {
+	dst = append(dst, `,{"key":`...)
+	dst = kvjson.AppendJSONString(dst, key)
+	return append(dst, `,"value":`...)
+}
+
+func appendStringValue(dst []byte, value string) []byte {
+	dst = append(dst, `{"stringValue":`...)
+	dst = kvjson.AppendJSONString(dst, value)
+	return append(dst, '}')
+}
@@ -0,0 +1,143 @@
+// Copyright 2026 xgfone
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package otlp
+
+import (
+	"bytes"
+	"encoding/json"
+	"math"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/xgfone/go-log"
+	"github.com/xgfone/go-log/encoder"
+)
+
+func TestParseSeverity(t *testing.T) {
+	for level, expect := range map[string]Severity{
+		"trace":   Trace,
+		"trace5":  Trace + 1,
+		"debug":   Debug,
+		"info":    Info,
+		"info19":  Info + 3,
+		"warn":    Warn,
+		"error":   Error,
+		"alert":   Error + 3,
+		"panic":   Fatal,
+		"panic5":  Fatal + 2,
+		"fatal":   Fatal + 3,
+		"unknown": Info,
+	} {
+		if severity := ParseSeverity(level); severity != expect {
+			t.Errorf("%s: expect severity %d, but got %d", level, expect, severity)
+		}
+	}
+}
+
+type object struct{}
+
+func (object) MarshalLogObject(enc encoder.FieldEncoder) {
+	enc.AddAny("name", "xgfone")
+	enc.AddAny("age", 18)
+}
+
+func decodeRecord(t *testing.T, data []byte) map[string]interface{} {
+	var result map[string]interface{}
+	if err := json.Unmarshal(data, &result); err != nil {
+		t.Fatalf("invalid json '%s': %s", data, err)
+	}
+	return result
+}
+
+func TestEncoder(t *testing.T) {
+	defer func(now func() time.Time) { encoder.Now = now }(encoder.Now)
+	encoder.Now = func() time.Time { return time.Unix(1700000000, 5) }
+
+	buf := bytes.NewBuffer(nil)
+	logger := log.New("").WithWriter(buf).WithEncoder(NewEncoder()).
+		WithContext("trace_id", "0AF7651916CD43DD8448EB211C80319C")
+
+	logger.Warn().
+		Int("int", 123).
+		Float64("float", 1.5).
+		Float64("nan", math.NaN()).
+		Bool("bool", true).
+		StrSlice("stacks", []string{"file1:1", "file2:2"}).
+		Kv("obj", object{}).
+		Kv("nil", nil).
+		Kv("span_id", [8]byte{0xb7, 0xad, 0x6b, 0x71, 0x69, 0x20, 0x33, 0x31}).
+		Printf("msg")
+
+	expect := map[string]interface{}{
+		"timeUnixNano":         "1700000000000000005",
+		"observedTimeUnixNano": "1700000000000000005",
+		"severityNumber":       float64(Warn),
+		"severityText":         "warn",
+		"traceId":              "0af7651916cd43dd8448eb211c80319c",
+		"spanId":               "b7ad6b7169203331",
+		"body":                 map[string]interface{}{"stringValue": "msg"},
+		"attributes": []interface{}{
+			map[string]interface{}{"key": "int", "value": map[string]interface{}{"intValue": "123"}},
+			map[string]interface{}{"key": "float", "value": map[string]interface{}{"doubleValue": 1.5}},
+			map[string]interface{}{"key": "nan", "value": map[string]interface{}{"doubleValue": "NaN"}},
+			map[string]interface{}{"key": "bool", "value": map[string]interface{}{"boolValue": true}},
+			map[string]interface{}{"key": "stacks", "value": map[string]interface{}{
+				"arrayValue": map[string]interface{}{"values": []interface{}{
+					map[string]interface{}{"stringValue": "file1:1"},
+					map[string]interface{}{"stringValue": "file2:2"},
+				}},
+			}},
+			map[string]interface{}{"key": "obj.name", "value": map[string]interface{}{"stringValue": "xgfone"}},
+			map[string]interface{}{"key": "obj.age", "value": map[string]interface{}{"intValue": "18"}},
+			map[string]interface{}{"key": "nil", "value": map[string]interface{}{}},
+		},
+	}
+
+	if result := decodeRecord(t, buf.Bytes()); !reflect.DeepEqual(result, expect) {
+		t.Errorf("expect '%v', but got '%v'", expect, result)
+	}
+
+	// The invalid trace id is encoded as an attribute.
+	buf.Reset()
+	log.New("test").WithWriter(buf).WithEncoder(NewEncoder()).
+		Info().Str("trace_id", "invalid").Printf("")
+	expect = map[string]interface{}{
+		"timeUnixNano":         "1700000000000000005",
+		"observedTimeUnixNano": "1700000000000000005",
+		"severityNumber":       float64(Info),
+		"severityText":         "info",
+		"body":                 map[string]interface{}{"stringValue": ""},
+		"attributes": []interface{}{
+			map[string]interface{}{"key": "logger", "value": map[string]interface{}{"stringValue": "test"}},
+			map[string]interface{}{"key": "trace_id", "value": map[string]interface{}{"stringValue": "invalid"}},
+		},
+	}
+
+	if result := decodeRecord(t, buf.Bytes()); !reflect.DeepEqual(result, expect) {
+		t.Errorf("expect '%v', but got '%v'", expect, result)
+	}
+
+	// No attributes
+	buf.Reset()
+	log.New("").WithWriter(buf).WithEncoder(NewEncoder()).
+		Info().Str("span_id", "B7AD6B7169203331").Printf("")
+	if s, expect := buf.String(), `{"timeUnixNano":"1700000000000000005",`+
+		`"observedTimeUnixNano":"1700000000000000005","severityNumber":9,`+
+		`"severityText":"info","attributes":[],"spanId":"b7ad6b7169203331",`+
+		`"body":{"stringValue":""}}`; s != expect {
+		t.Errorf("expect '%s', but got '%s'", expect, s)
+	}
+}
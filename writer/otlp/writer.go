@@ -0,0 +1,106 @@
+// Copyright 2026 xgfone
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package otlp
+
+import (
+	"bytes"
+	"os"
+	"sort"
+
+	"github.com/xgfone/go-log/encoder/kvjson"
+	"github.com/xgfone/go-log/writer"
+)
+
+var _ writer.HTTPBodyBuilder = Body{}
+
+// Body builds the body of ExportLogsServiceRequest in the OTLP JSON
+// from the records encoded by Encoder, which puts all the records
+// into a scope of a resource.
+type Body struct {
+	// Resource is the attributes of the resource, such as "service.name".
+	//
+	// Default: nil
+	Resource map[string]string
+
+	// ScopeName and ScopeVersion are the name and version of the
+	// instrumentation scope, which are omitted if empty.
+	//
+	// Default: "", ""
+	ScopeName    string
+	ScopeVersion string
+}
+
+// NewBody returns a new Body with the resource attributes "service.name"
+// and "host.name", the latter of which is os.Hostname().
+func NewBody(serviceName string) Body {
+	host, _ := os.Hostname()
+	return Body{Resource: map[string]string{
+		"service.name": serviceName,
+		"host.name":    host,
+	}}
+}
+
+// ContentType implements the interface writer.HTTPBodyBuilder.
+func (b Body) ContentType() string { return "application/json" }
+
+// AppendBody implements the interface writer.HTTPBodyBuilder.
+func (b Body) AppendBody(dst []byte, records []writer.HTTPRecord) []byte {
+	keys := make([]string, 0, len(b.Resource))
+	for key := range b.Resource {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	dst = append(dst, `{"resourceLogs":[{"resource":{"attributes":[`...)
+	for i, key := range keys {
+		if i > 0 {
+			dst = append(dst, ',')
+		}
+		dst = append(dst, `{"key":`...)
+		dst = kvjson.AppendJSONString(dst, key)
+		dst = append(dst, `,"value":`...)
+		dst = append(appendStringValue(dst, b.Resource[key]), '}')
+	}
+
+	dst = append(dst, `]},"scopeLogs":[{"scope":{`...)
+	if b.ScopeName != "" {
+		dst = append(dst, `"name":`...)
+		dst = kvjson.AppendJSONString(dst, b.ScopeName)
+	}
+	if b.ScopeVersion != "" {
+		if b.ScopeName != "" {
+			dst = append(dst, ',')
+		}
+		dst = append(dst, `"version":`...)
+		dst = kvjson.AppendJSONString(dst, b.ScopeVersion)
+	}
+
+	dst = append(dst, `},"logRecords":[`...)
+	for i, r := range records {
+		if i > 0 {
+			dst = append(dst, ',')
+		}
+		dst = append(dst, bytes.TrimRight(r.Data, "\r\n")...)
+	}
+	return append(dst, `]}]}]}`...)
+}
+
+// NewWriter returns a new writer to export the records encoded by Encoder
+// to the OTLP/HTTP endpoint at url in batches, such as
+// "http://127.0.0.1:4318/v1/logs", which uses body as options.Body.
+func NewWriter(url string, body Body, options writer.HTTPOptions) *writer.HTTPLevelWriter {
+	options.Body = body
+	return writer.HTTPWriter(url, options)
+}
@@ -0,0 +1,137 @@
+// Copyright 2026 xgfone
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package otlp
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"sync"
+	"testing"
+
+	"github.com/xgfone/go-log"
+	"github.com/xgfone/go-log/writer"
+)
+
+type keyValue struct {
+	Key   string
+	Value struct {
+		StringValue string
+	}
+}
+
+// exportRequest is the part of ExportLogsServiceRequest in JSON.
+type exportRequest struct {
+	ResourceLogs []struct {
+		Resource struct {
+			Attributes []keyValue
+		}
+		ScopeLogs []struct {
+			Scope struct {
+				Name    string
+				Version string
+			}
+			LogRecords []struct {
+				SeverityNumber int
+				SeverityText   string
+				TraceID        string
+				SpanID         string
+				Attributes     []keyValue
+				Body           struct {
+					StringValue string
+				}
+			}
+		}
+	}
+}
+
+func TestWriter(t *testing.T) {
+	var lock sync.Mutex
+	var requests []exportRequest
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/logs" || r.Header.Get("Content-Type") != "application/json" {
+			rw.WriteHeader(400)
+			return
+		}
+
+		var req exportRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			rw.WriteHeader(400)
+			return
+		}
+
+		lock.Lock()
+		requests = append(requests, req)
+		lock.Unlock()
+		rw.Write([]byte(`{}`))
+	}))
+	defer server.Close()
+
+	body := NewBody("myapp")
+	body.Resource["host.name"] = "localhost"
+	body.ScopeName = "github.com/xgfone/go-log"
+
+	w := NewWriter(server.URL+"/v1/logs", body, writer.HTTPOptions{MaxLatency: -1})
+	logger := log.New("").WithWriter(w).WithEncoder(NewEncoder())
+	logger.Info().Str("trace_id", "0af7651916cd43dd8448eb211c80319c").Str("key", "value").Printf("msg1")
+	logger.Error().Printf("msg2")
+	if err := w.Flush(); err != nil {
+		t.Fatal(err)
+	}
+	w.Close()
+
+	lock.Lock()
+	defer lock.Unlock()
+	if len(requests) != 1 || len(requests[0].ResourceLogs) != 1 {
+		t.Fatalf("unexpected requests: %+v", requests)
+	}
+
+	resource := requests[0].ResourceLogs[0]
+	attrs := make(map[string]string)
+	for _, kv := range resource.Resource.Attributes {
+		attrs[kv.Key] = kv.Value.StringValue
+	}
+	if expect := map[string]string{"service.name": "myapp", "host.name": "localhost"}; !reflect.DeepEqual(attrs, expect) {
+		t.Errorf("expect resource attributes %v, but got %v", expect, attrs)
+	}
+
+	if len(resource.ScopeLogs) != 1 {
+		t.Fatalf("expect %d scope, but got %d", 1, len(resource.ScopeLogs))
+	}
+	scope := resource.ScopeLogs[0]
+	if scope.Scope.Name != body.ScopeName {
+		t.Errorf("expect scope name '%s', but got '%s'", body.ScopeName, scope.Scope.Name)
+	}
+
+	if len(scope.LogRecords) != 2 {
+		t.Fatalf("expect %d records, but got %d", 2, len(scope.LogRecords))
+	}
+
+	r := scope.LogRecords[0]
+	if r.SeverityNumber != int(Info) || r.SeverityText != "info" || r.Body.StringValue != "msg1" ||
+		r.TraceID != "0af7651916cd43dd8448eb211c80319c" || r.SpanID != "" {
+		t.Errorf("unexpected record: %+v", r)
+	}
+	if len(r.Attributes) != 1 || r.Attributes[0].Key != "key" || r.Attributes[0].Value.StringValue != "value" {
+		t.Errorf("unexpected attributes: %+v", r.Attributes)
+	}
+
+	r = scope.LogRecords[1]
+	if r.SeverityNumber != int(Error) || r.SeverityText != "error" || r.Body.StringValue != "msg2" ||
+		r.TraceID != "" || len(r.Attributes) != 0 {
+		t.Errorf("unexpected record: %+v", r)
+	}
+}
@@ -0,0 +1,184 @@
+// Copyright 2026 xgfone
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package writer
+
+import (
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"time"
+)
+
+// Retention is the retention policy of the rotated backups, which is
+// evaluated after each rotation and when opening the file at startup.
+type Retention struct {
+	// MaxAge is the maximum age of the backups by the modification time
+	// if greater than 0, and the older backups will be deleted.
+	//
+	// Default: 0
+	MaxAge time.Duration
+
+	// MaxBackupSize is the maximum total size of all the backups if greater
+	// than 0, and the oldest backups will be deleted until the total size
+	// does not exceed it.
+	//
+	// Default: 0
+	MaxBackupSize int64
+
+	// OnDelete is called after a backup is deleted by the retention policy,
+	// which may be called in a background goroutine, but never concurrently.
+	//
+	// Default: nil
+	OnDelete func(filename string)
+}
+
+func (r Retention) enabled() bool { return r.MaxAge > 0 || r.MaxBackupSize > 0 }
+
+// clean deletes the backups which do not satisfy the retention policy.
+func (r Retention) clean(backups []string, now time.Time) {
+	if !r.enabled() || len(backups) == 0 {
+		return
+	}
+
+	files := make(backupFiles, 0, len(backups))
+	for _, name := range backups {
+		if info, err := os.Lstat(name); err == nil && info.Mode().IsRegular() {
+			files = append(files, backupFile{name: name, info: info})
+		}
+	}
+	sort.Sort(files)
+
+	var total int64
+	deadline := now.Add(-r.MaxAge)
+	for _, file := range files {
+		size := file.info.Size()
+		if (r.MaxAge > 0 && file.info.ModTime().Before(deadline)) ||
+			(r.MaxBackupSize > 0 && total+size > r.MaxBackupSize) {
+			if err := os.Remove(file.name); err == nil && r.OnDelete != nil {
+				r.OnDelete(file.name)
+			}
+			continue
+		}
+		total += size
+	}
+}
+
+type backupFile struct {
+	name string
+	info os.FileInfo
+}
+
+// backupFiles is sorted from the newest to the oldest.
+type backupFiles []backupFile
+
+func (fs backupFiles) Len() int      { return len(fs) }
+func (fs backupFiles) Swap(i, j int) { fs[i], fs[j] = fs[j], fs[i] }
+func (fs backupFiles) Less(i, j int) bool {
+	if ti, tj := fs[i].info.ModTime(), fs[j].info.ModTime(); !ti.Equal(tj) {
+		return ti.After(tj)
+	}
+	return fs[i].name < fs[j].name
+}
+
+// timedBackups returns the backups of the file rotated by the time,
+// which match the filename pattern, except the current file.
+//
+// The candidates listed by the glob, where each conversion specification
+// is replaced with '*', are checked by the regular expression, where each
+// conversion specification only matches what Strftime formats, so that
+// the other files in the same directory, such as "db.log" for the pattern
+// "%Y%m%d.log", are not taken as the backups.
+func timedBackups(pattern, current string) (backups []string) {
+	pattern = filepath.Clean(pattern)
+
+	var glob []byte
+	expr := []byte{'^'}
+	for i, _len := 0, len(pattern); i < _len; i++ {
+		if pattern[i] != '%' || i+1 == _len {
+			glob = append(glob, pattern[i])
+			expr = append(expr, regexp.QuoteMeta(pattern[i:i+1])...)
+			continue
+		}
+
+		i++
+		if pattern[i] == '%' {
+			glob = append(glob, '%')
+			expr = append(expr, '%')
+		} else {
+			glob = append(glob, '*')
+			expr = append(expr, strftimeExpr(pattern[i])...)
+		}
+	}
+	expr = append(expr, `(\.[0-9]+)?$`...)
+
+	re, err := regexp.Compile(string(expr))
+	if err != nil {
+		return nil
+	}
+
+	files, _ := filepath.Glob(string(glob))
+	indexed, _ := filepath.Glob(string(glob) + ".*")
+	files = append(files, indexed...)
+
+	current = filepath.Clean(current)
+	exists := make(map[string]struct{}, len(files))
+	for _, name := range files {
+		if _, ok := exists[name]; !ok && name != current && re.MatchString(name) {
+			exists[name] = struct{}{}
+			backups = append(backups, name)
+		}
+	}
+	return
+}
+
+// strftimeExpr returns the regular expression matching the result
+// of the conversion specification c formatted by Strftime.
+func strftimeExpr(c byte) string {
+	switch c {
+	case 'Y':
+		return "[0-9]{4}"
+	case 'y', 'm', 'd', 'H', 'I', 'M', 'S':
+		return "[0-9]{2}"
+	case 'j':
+		return "[0-9]{3}"
+	case 's':
+		return "[0-9]+"
+	case 'b', 'a':
+		return "[A-Z][a-z]{2}"
+	case 'B', 'A':
+		return "[A-Z][a-z]+"
+	case 'p':
+		return "(AM|PM)"
+	case 'z':
+		return "[+-][0-9]{4}"
+	case 'Z':
+		return "[A-Za-z0-9+-]+"
+	default:
+		return regexp.QuoteMeta("%" + string(c))
+	}
+}
+
+func isDigits(s string) bool {
+	if s == "" {
+		return false
+	}
+	for i := 0; i < len(s); i++ {
+		if s[i] < '0' || s[i] > '9' {
+			return false
+		}
+	}
+	return true
+}
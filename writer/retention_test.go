@@ -0,0 +1,211 @@
+// Copyright 2026 xgfone
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package writer
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func createBackups(t *testing.T, dir string, now time.Time, ages map[string]time.Duration) {
+	for name, age := range ages {
+		path := filepath.Join(dir, name)
+		if err := ioutil.WriteFile(path, []byte("0123456789"), 0644); err != nil {
+			t.Fatal(err)
+		}
+
+		mtime := now.Add(-age)
+		if err := os.Chtimes(path, mtime, mtime); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func checkDeleted(t *testing.T, deleted []string, expects ...string) {
+	for i := range deleted {
+		deleted[i] = filepath.Base(deleted[i])
+	}
+	sort.Strings(deleted)
+	sort.Strings(expects)
+	if strings.Join(deleted, ",") != strings.Join(expects, ",") {
+		t.Errorf("expect deleted files %v, but got %v", expects, deleted)
+	}
+}
+
+func TestSizedRotatingFileRetention(t *testing.T) {
+	dir, err := ioutil.TempDir("", "sized_retention")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	now := time.Now()
+	createBackups(t, dir, now, map[string]time.Duration{
+		"app.log.1":    time.Hour,
+		"app.log.2.gz": 2 * time.Hour,
+		"app.log.3":    3 * time.Hour,
+		"app.log.4":    4 * time.Hour,
+		"app.log.5":    8 * 24 * time.Hour,
+		"app.log.x":    8 * 24 * time.Hour,
+	})
+
+	var deleted []string
+	file := NewSizedRotatingFile(filepath.Join(dir, "app.log"), 10, 10)
+	file.MaxAge = 7 * 24 * time.Hour
+	file.MaxBackupSize = 30
+	file.OnDelete = func(name string) { deleted = append(deleted, name) }
+	defer file.Close()
+
+	if _, err := file.Write([]byte("abc")); err != nil {
+		t.Fatal(err)
+	}
+	checkDeleted(t, deleted, "app.log.4", "app.log.5")
+
+	// Rotate: app.log(3) -> app.log.1, the total size is 3+10+10+10 > 30.
+	deleted = nil
+	if _, err := file.Write([]byte("0123456789")); err != nil {
+		t.Fatal(err)
+	}
+	checkDeleted(t, deleted, "app.log.4")
+}
+
+func TestSizedRotatingFileRetentionSerial(t *testing.T) {
+	dir, err := ioutil.TempDir("", "sized_retention")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	createBackups(t, dir, time.Now(), map[string]time.Duration{
+		"app.log.1.gz": 2 * time.Hour,
+		"app.log.2.gz": 3 * time.Hour,
+		"app.log.3.gz": 4 * time.Hour,
+		"app.log.4.gz": 5 * time.Hour,
+	})
+
+	// The rotated file left by the last running is handled by the worker,
+	// which evaluates the retention policy in background.
+	ioutil.WriteFile(filepath.Join(dir, "app.log.100.rotated"), []byte("old"), 0644)
+
+	var running, overlaps int32
+	var deleted []string
+	file := NewSizedRotatingFile(filepath.Join(dir, "app.log"), 10, 10)
+	file.Compress = true
+	file.MaxAge = time.Hour
+	file.OnDelete = func(name string) {
+		if atomic.AddInt32(&running, 1) > 1 {
+			atomic.AddInt32(&overlaps, 1)
+		}
+		time.Sleep(time.Millisecond * 20)
+		deleted = append(deleted, name)
+		atomic.AddInt32(&running, -1)
+	}
+
+	if _, err := file.Write([]byte("abc")); err != nil {
+		t.Fatal(err)
+	}
+	file.Close()
+
+	if n := atomic.LoadInt32(&overlaps); n > 0 {
+		t.Errorf("OnDelete is called concurrently for %d times", n)
+	}
+
+	// The old backups are renamed by the worker, so only check the number.
+	if len(deleted) != 4 {
+		t.Errorf("expect %d deleted files, but got %v", 4, deleted)
+	}
+}
+
+func TestTimedRotatingFileRetention(t *testing.T) {
+	dir, err := ioutil.TempDir("", "timed_retention")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	now := time.Date(2026, time.October, 16, 12, 0, 0, 0, time.UTC)
+	createBackups(t, dir, now, map[string]time.Duration{
+		"app.2026-10-15.log":   24 * time.Hour,
+		"app.2026-10-15.log.1": 23 * time.Hour,
+		"app.2026-10-10.log":   6 * 24 * time.Hour,
+		"app.2026-10-01.log":   15 * 24 * time.Hour,
+		"app.2026-10-01.log.x": 15 * 24 * time.Hour,
+		"other.log":            15 * 24 * time.Hour,
+	})
+
+	var deleted []string
+	file := NewTimedRotatingFile(filepath.Join(dir, "app.%Y-%m-%d.log"), 0)
+	file.Location = time.UTC
+	file.Now = func() time.Time { return now }
+	file.MaxAge = 7 * 24 * time.Hour
+	file.OnDelete = func(name string) { deleted = append(deleted, name) }
+	defer file.Close()
+
+	if _, err := file.Write([]byte("abc")); err != nil {
+		t.Fatal(err)
+	}
+	checkDeleted(t, deleted, "app.2026-10-01.log")
+
+	deleted = nil
+	now = now.Add(24 * time.Hour)
+	file.MaxAge = 0
+	file.MaxBackupSize = 25
+	if _, err := file.Write([]byte("abc")); err != nil {
+		t.Fatal(err)
+	}
+	checkDeleted(t, deleted, "app.2026-10-10.log")
+}
+
+func TestTimedRotatingFileRetentionSiblings(t *testing.T) {
+	dir, err := ioutil.TempDir("", "timed_retention_siblings")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	now := time.Date(2026, time.October, 16, 12, 0, 0, 0, time.UTC)
+	createBackups(t, dir, now, map[string]time.Duration{
+		"20261014.log":   48 * time.Hour,
+		"20261014.log.1": 48 * time.Hour,
+		"database.log":   48 * time.Hour,
+		"2026101.log":    48 * time.Hour,
+		"20261014.log.x": 48 * time.Hour,
+	})
+
+	var deleted []string
+	file := NewTimedRotatingFile(filepath.Join(dir, "%Y%m%d.log"), 0)
+	file.Location = time.UTC
+	file.Now = func() time.Time { return now }
+	file.MaxAge = time.Hour
+	file.OnDelete = func(name string) { deleted = append(deleted, name) }
+	defer file.Close()
+
+	if _, err := file.Write([]byte("abc")); err != nil {
+		t.Fatal(err)
+	}
+	checkDeleted(t, deleted, "20261014.log", "20261014.log.1")
+
+	for _, name := range []string{"database.log", "2026101.log", "20261014.log.x"} {
+		if _, err := os.Stat(filepath.Join(dir, name)); err != nil {
+			t.Errorf("expect the file '%s' to be kept, but got %v", name, err)
+		}
+	}
+}
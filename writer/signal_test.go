@@ -0,0 +1,50 @@
+// Copyright 2026 xgfone
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build !windows && !plan9
+// +build !windows,!plan9
+
+package writer
+
+import (
+	"os"
+	"sync/atomic"
+	"syscall"
+	"testing"
+	"time"
+)
+
+type testReopener struct{ reopens int32 }
+
+func (r *testReopener) Write(p []byte) (int, error) { return len(p), nil }
+
+func (r *testReopener) Reopen() error {
+	atomic.AddInt32(&r.reopens, 1)
+	return nil
+}
+
+func TestReopenOnSignal(t *testing.T) {
+	r := new(testReopener)
+	stop := ReopenOnSignal(SafeWriter(r), syscall.SIGUSR1)
+	defer stop()
+
+	syscall.Kill(os.Getpid(), syscall.SIGUSR1)
+	for i := 0; i < 100 && atomic.LoadInt32(&r.reopens) == 0; i++ {
+		time.Sleep(time.Millisecond * 10)
+	}
+
+	if n := atomic.LoadInt32(&r.reopens); n != 1 {
+		t.Errorf("expect reopening %d time, but got %d", 1, n)
+	}
+}
@@ -0,0 +1,379 @@
+// Copyright 2026 xgfone
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package syslog provides a syslog writer speaking RFC 5424 or RFC 3164
+// over UDP, TCP and the unix sockets.
+package syslog
+
+import (
+	"errors"
+	"net"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/xgfone/go-log"
+	"github.com/xgfone/go-log/writer"
+)
+
+var _ writer.LevelWriter = &Writer{}
+
+// Format is the format of the syslog message.
+type Format int
+
+// Predefine some syslog formats.
+const (
+	// RFC5424 is the format like
+	// "<PRI>1 TIMESTAMP HOSTNAME APP-NAME PROCID MSGID STRUCTURED-DATA MSG".
+	RFC5424 Format = iota
+
+	// RFC3164 is the legacy BSD format like "<PRI>TIMESTAMP HOSTNAME TAG[PID]: MSG".
+	RFC3164
+)
+
+// Facility is the syslog facility.
+type Facility int
+
+// Predefine the syslog facilities.
+const (
+	Kern Facility = iota
+	User
+	Mail
+	Daemon
+	Auth
+	Syslog
+	Lpr
+	News
+	Uucp
+	Cron
+	Authpriv
+	Ftp
+	_ // NTP
+	_ // Log audit
+	_ // Log alert
+	_ // Clock daemon
+	Local0
+	Local1
+	Local2
+	Local3
+	Local4
+	Local5
+	Local6
+	Local7
+)
+
+// Severity is the syslog severity.
+type Severity int
+
+// Predefine the syslog severities.
+const (
+	Emerg Severity = iota
+	Alert
+	Crit
+	Err
+	Warning
+	Notice
+	Info
+	Debug
+)
+
+// LevelToSeverity converts the level of the logger to the syslog severity.
+//
+//	[LvlTrace, LvlInfo)  => Debug
+//	[LvlInfo,  LvlWarn)  => Info
+//	[LvlWarn,  LvlError) => Warning
+//	[LvlError, LvlAlert) => Err
+//	[LvlAlert, LvlPanic) => Alert
+//	[LvlPanic, LvlFatal) => Crit
+//	[LvlFatal, ...)      => Emerg
+func LevelToSeverity(level int) Severity {
+	switch {
+	case level < log.LvlInfo:
+		return Debug
+	case level < log.LvlWarn:
+		return Info
+	case level < log.LvlError:
+		return Warning
+	case level < log.LvlAlert:
+		return Err
+	case level < log.LvlPanic:
+		return Alert
+	case level < log.LvlFatal:
+		return Crit
+	default:
+		return Emerg
+	}
+}
+
+// ParseSeverity parses the level string formatted by log.FormatLevel,
+// such as "info" and "info5", by log.ParseFormattedLevel, and converts it
+// to the syslog severity by LevelToSeverity. The unknown level is parsed as log.LvlInfo.
+func ParseSeverity(level string) Severity {
+	return LevelToSeverity(log.ParseFormattedLevel(level, log.LvlInfo))
+}
+
+// The addresses of the local syslog server tried in turn if the network is empty.
+var localAddrs = []string{"/dev/log", "/var/run/syslog", "/var/run/log"}
+
+// Writer is a thread-safe syslog writer, which implements the interfaces
+// writer.LevelWriter and io.Closer.
+//
+// It connects to the syslog server lazily when writing the first message,
+// and reconnects and resends the message once if failing to write it.
+type Writer struct {
+	// Format is the format of the syslog message.
+	//
+	// Default: RFC5424
+	Format Format
+
+	// Facility is the syslog facility.
+	//
+	// Default: User
+	Facility Facility
+
+	// Hostname is the HOSTNAME field of the syslog message.
+	//
+	// Default: os.Hostname()
+	Hostname string
+
+	// AppName is the APP-NAME field of RFC5424, or the TAG field of RFC3164.
+	//
+	// Default: filepath.Base(os.Args[0])
+	AppName string
+
+	// ProcID is the PROCID field of RFC5424, or the PID in the TAG field
+	// of RFC3164.
+	//
+	// Default: strconv.Itoa(os.Getpid())
+	ProcID string
+
+	// MsgID is the MSGID field of RFC5424, which is ignored by RFC3164.
+	//
+	// Default: "-"
+	MsgID string
+
+	// StructuredData is the STRUCTURED-DATA field of RFC5424, such as
+	// `[app@32473 env="prod"]`, which is ignored by RFC3164.
+	//
+	// Default: "-"
+	StructuredData string
+
+	// Timeout is the timeout to connect to the server and write the message.
+	//
+	// Default: 5s
+	Timeout time.Duration
+
+	network string
+	addr    string
+	local   bool // Connect to the local syslog server.
+
+	lock  sync.Mutex
+	conn  net.Conn
+	buf   []byte
+	frame []byte
+}
+
+// NewWriter returns a new syslog writer to send the message
+// to the syslog server at addr by the network.
+//
+// network is one of "udp", "tcp", "unix" and "unixgram", or their variants
+// like "udp4". For the stream sockets, the message is framed by the octet
+// counting of RFC 6587, that's, "MSG-LEN SP SYSLOG-MSG".
+//
+// If network is empty, it connects to the local syslog server by the unix
+// socket, such as "/dev/log", and addr is ignored. The local socket is
+// discovered again when reconnecting, and the message sent by the stream
+// socket is terminated by the newline instead of the octet counting.
+func NewWriter(network, addr string) *Writer {
+	hostname, _ := os.Hostname()
+	return &Writer{
+		Format:   RFC5424,
+		Facility: User,
+		Hostname: hostname,
+		AppName:  filepath.Base(os.Args[0]),
+		ProcID:   strconv.Itoa(os.Getpid()),
+		MsgID:    "-",
+		Timeout:  time.Second * 5,
+
+		StructuredData: "-",
+
+		network: network,
+		addr:    addr,
+		local:   network == "",
+	}
+}
+
+// Close closes the connection to the syslog server.
+func (w *Writer) Close() (err error) {
+	w.lock.Lock()
+	if w.conn != nil {
+		err = w.conn.Close()
+		w.conn = nil
+	}
+	w.lock.Unlock()
+	return
+}
+
+// Write implements the interface io.Writer, which sends the message
+// with the severity Info.
+func (w *Writer) Write(p []byte) (n int, err error) {
+	return w.send(Info, p)
+}
+
+// WriteLevel implements the interface writer.LevelWriter, which sends
+// the message with the severity converted from level by LevelToSeverity.
+func (w *Writer) WriteLevel(level int, p []byte) (n int, err error) {
+	return w.send(LevelToSeverity(level), p)
+}
+
+func (w *Writer) send(severity Severity, p []byte) (n int, err error) {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+
+	w.buf = w.format(w.buf[:0], severity, time.Now(), trimNewline(p))
+	for i := 0; i < 2; i++ {
+		if w.conn == nil {
+			if err = w.connect(); err != nil {
+				return
+			}
+		}
+
+		if err = w.write(w.buf); err == nil {
+			return len(p), nil
+		}
+
+		// Reconnect and resend the message.
+		w.conn.Close()
+		w.conn = nil
+	}
+	return
+}
+
+func (w *Writer) connect() (err error) {
+	if !w.local {
+		w.conn, err = net.DialTimeout(w.network, w.addr, w.Timeout)
+		return
+	}
+
+	for _, network := range []string{"unixgram", "unix"} {
+		for _, addr := range localAddrs {
+			if w.conn, err = net.DialTimeout(network, addr, w.Timeout); err == nil {
+				w.network, w.addr = network, addr
+				return
+			}
+		}
+	}
+	return errors.New("failed to connect to the local syslog server")
+}
+
+func (w *Writer) write(msg []byte) (err error) {
+	if w.Timeout > 0 {
+		w.conn.SetWriteDeadline(time.Now().Add(w.Timeout))
+	}
+
+	if w.local && isStream(w.network) {
+		w.frame = append(w.frame[:0], msg...)
+		w.frame = append(w.frame, '\n')
+		msg = w.frame
+	} else if isStream(w.network) {
+		w.frame = strconv.AppendInt(w.frame[:0], int64(len(msg)), 10)
+		w.frame = append(w.frame, ' ')
+		w.frame = append(w.frame, msg...)
+		msg = w.frame
+	}
+
+	_, err = w.conn.Write(msg)
+	return
+}
+
+func (w *Writer) format(buf []byte, severity Severity, now time.Time, msg []byte) []byte {
+	buf = append(buf, '<')
+	buf = strconv.AppendInt(buf, int64(int(w.Facility)*8+int(severity)), 10)
+	buf = append(buf, '>')
+
+	switch w.Format {
+	case RFC3164:
+		buf = now.AppendFormat(buf, time.Stamp)
+		buf = append(buf, ' ')
+		buf = appendField(buf, w.Hostname, 255)
+		buf = append(buf, ' ')
+		buf = appendField(buf, w.AppName, 32)
+		if w.ProcID != "" && w.ProcID != "-" {
+			buf = append(buf, '[')
+			buf = appendField(buf, w.ProcID, 128)
+			buf = append(buf, ']')
+		}
+		buf = append(buf, ':', ' ')
+
+	default:
+		buf = append(buf, '1', ' ')
+		buf = now.AppendFormat(buf, "2006-01-02T15:04:05.000000Z07:00")
+		buf = append(buf, ' ')
+		buf = appendField(buf, w.Hostname, 255)
+		buf = append(buf, ' ')
+		buf = appendField(buf, w.AppName, 48)
+		buf = append(buf, ' ')
+		buf = appendField(buf, w.ProcID, 128)
+		buf = append(buf, ' ')
+		buf = appendField(buf, w.MsgID, 32)
+		buf = append(buf, ' ')
+		if w.StructuredData == "" {
+			buf = append(buf, '-')
+		} else {
+			buf = append(buf, w.StructuredData...)
+		}
+		buf = append(buf, ' ')
+	}
+
+	return append(buf, msg...)
+}
+
+// appendField appends the header field, which consists of the printable
+// US-ASCII characters except the space, and is "-" if empty.
+func appendField(buf []byte, field string, maxlen int) []byte {
+	if field == "" {
+		return append(buf, '-')
+	}
+
+	if len(field) > maxlen {
+		field = field[:maxlen]
+	}
+
+	for i := 0; i < len(field); i++ {
+		if c := field[i]; c > ' ' && c < 0x7f {
+			buf = append(buf, c)
+		} else {
+			buf = append(buf, '_')
+		}
+	}
+	return buf
+}
+
+func trimNewline(p []byte) []byte {
+	for len(p) > 0 && (p[len(p)-1] == '\n' || p[len(p)-1] == '\r') {
+		p = p[:len(p)-1]
+	}
+	return p
+}
+
+func isStream(network string) bool {
+	switch network {
+	case "tcp", "tcp4", "tcp6", "unix":
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,272 @@
+// Copyright 2026 xgfone
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package syslog
+
+import (
+	"bufio"
+	"io"
+	"io/ioutil"
+	"net"
+	"os"
+	"path/filepath"
+	"regexp"
+	"runtime"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/xgfone/go-log"
+)
+
+func newTestWriter(network, addr string) *Writer {
+	w := NewWriter(network, addr)
+	w.Hostname = "host"
+	w.AppName = "app"
+	w.ProcID = "123"
+	w.Timeout = time.Second
+	return w
+}
+
+func TestLevelToSeverity(t *testing.T) {
+	expects := map[int]Severity{
+		log.LvlTrace:     Debug,
+		log.LvlDebug:     Debug,
+		log.LvlInfo:      Info,
+		log.LvlInfo + 5:  Info,
+		log.LvlWarn:      Warning,
+		log.LvlError:     Err,
+		log.LvlAlert:     Alert,
+		log.LvlPanic:     Crit,
+		log.LvlFatal:     Emerg,
+		log.LvlFatal + 1: Emerg,
+	}
+
+	for level, expect := range expects {
+		if severity := LevelToSeverity(level); severity != expect {
+			t.Errorf("level %d: expect severity %d, but got %d", level, expect, severity)
+		}
+	}
+}
+
+func TestParseSeverity(t *testing.T) {
+	expects := map[string]Severity{
+		"trace":  Debug,
+		"info":   Info,
+		"info5":  Info,
+		"warn":   Warning,
+		"error5": Err,
+		"fatal":  Emerg,
+		"other":  Info,
+	}
+
+	for level, expect := range expects {
+		if severity := ParseSeverity(level); severity != expect {
+			t.Errorf("level %s: expect severity %d, but got %d", level, expect, severity)
+		}
+	}
+}
+
+func TestWriterUDP(t *testing.T) {
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	w := newTestWriter("udp", conn.LocalAddr().String())
+	w.Facility = Local0
+	w.MsgID = "ID1"
+	w.StructuredData = `[app@32473 env="test"]`
+	defer w.Close()
+
+	if _, err := w.WriteLevel(log.LvlAlert, []byte("msg\n")); err != nil {
+		t.Fatal(err)
+	}
+
+	buf := make([]byte, 1024)
+	conn.SetReadDeadline(time.Now().Add(time.Second))
+	n, _, err := conn.ReadFrom(buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	pattern := `^<129>1 \d{4}-\d\d-\d\dT\d\d:\d\d:\d\d\.\d{6}(Z|[+-]\d\d:\d\d) host app 123 ID1 \[app@32473 env="test"\] msg$`
+	if msg := string(buf[:n]); !regexp.MustCompile(pattern).MatchString(msg) {
+		t.Errorf("unexpected message '%s'", msg)
+	}
+}
+
+func TestWriterTCP(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ln.Close()
+
+	msgs := make(chan string, 16)
+	go func() {
+		for first := true; ; first = false {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+
+			r := bufio.NewReader(conn)
+			for {
+				length, err := r.ReadString(' ')
+				if err != nil {
+					break
+				}
+
+				n, _ := strconv.Atoi(strings.TrimSpace(length))
+				msg := make([]byte, n)
+				if _, err = io.ReadFull(r, msg); err != nil {
+					break
+				}
+				msgs <- string(msg)
+
+				if first { // Close the first connection to test the reconnection.
+					break
+				}
+			}
+			conn.Close()
+		}
+	}()
+
+	w := newTestWriter("tcp", ln.Addr().String())
+	w.Format = RFC3164
+	defer w.Close()
+
+	w.WriteLevel(log.LvlError, []byte("msg1\n"))
+	if msg := <-msgs; !strings.HasSuffix(msg, " host app[123]: msg1") || !strings.HasPrefix(msg, "<11>") {
+		t.Errorf("unexpected message '%s'", msg)
+	}
+
+	// The first connection has been closed by the server, so it should
+	// reconnect to the server after failing to write the message.
+	timeout := time.After(time.Second * 5)
+	for {
+		w.Write([]byte("msg2"))
+		select {
+		case msg := <-msgs:
+			if !strings.HasSuffix(msg, ": msg2") || !strings.HasPrefix(msg, "<14>") {
+				t.Errorf("unexpected message '%s'", msg)
+			}
+			return
+		case <-timeout:
+			t.Fatal("timeout to wait for the reconnection")
+		case <-time.After(time.Millisecond * 10):
+		}
+	}
+}
+
+func TestWriterUnixgram(t *testing.T) {
+	if runtime.GOOS == "windows" || runtime.GOOS == "plan9" {
+		t.Skip("unixgram is not supported")
+	}
+
+	dir, err := ioutil.TempDir("", "syslog")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	addr := filepath.Join(dir, "log.sock")
+	conn, err := net.ListenPacket("unixgram", addr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	w := newTestWriter("unixgram", addr)
+	w.ProcID = ""
+	defer w.Close()
+
+	if _, err := w.WriteLevel(log.LvlDebug, []byte("msg")); err != nil {
+		t.Fatal(err)
+	}
+
+	buf := make([]byte, 1024)
+	conn.SetReadDeadline(time.Now().Add(time.Second))
+	n, _, err := conn.ReadFrom(buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if msg := string(buf[:n]); !strings.HasPrefix(msg, "<15>1 ") || !strings.HasSuffix(msg, " host app - - - msg") {
+		t.Errorf("unexpected message '%s'", msg)
+	}
+}
+
+func TestWriterLocal(t *testing.T) {
+	if runtime.GOOS == "windows" || runtime.GOOS == "plan9" {
+		t.Skip("unix is not supported")
+	}
+
+	dir, err := ioutil.TempDir("", "syslog")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	addr := filepath.Join(dir, "log.sock")
+	defer func(addrs []string) { localAddrs = addrs }(localAddrs)
+	localAddrs = []string{filepath.Join(dir, "nonexistent.sock"), addr}
+
+	ln, err := net.Listen("unix", addr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ln.Close()
+
+	msgs := make(chan string, 16)
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+
+			// Close the connection after reading a message like the restart
+			// of the syslog server to test the reconnection.
+			if msg, err := bufio.NewReader(conn).ReadString('\n'); err == nil {
+				msgs <- msg
+			}
+			conn.Close()
+		}
+	}()
+
+	w := newTestWriter("", "")
+	defer w.Close()
+
+	timeout := time.After(time.Second * 5)
+	for _, expect := range []string{"msg1", "msg2"} {
+	LOOP:
+		for {
+			w.Write([]byte(expect))
+			select {
+			case msg := <-msgs:
+				if !strings.HasPrefix(msg, "<14>1 ") || !strings.HasSuffix(msg, " host app 123 - - "+expect+"\n") {
+					t.Errorf("unexpected message '%s'", msg)
+				}
+				break LOOP
+			case <-timeout:
+				t.Fatal("timeout to wait for the reconnection")
+			case <-time.After(time.Millisecond * 10):
+			}
+		}
+	}
+}
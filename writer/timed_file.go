@@ -0,0 +1,530 @@
+// Copyright 2026 xgfone
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package writer
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+// NewTimedRotatingFile returns a new TimedRotatingFile, which is not thread-safe.
+//
+// pattern is the strftime-style filename pattern, such as "app.%Y-%m-%d.log",
+// which is formatted by the start time of the interval. See Strftime.
+// It panics if pattern contains no conversion specification of the time,
+// such as "app.log", because the file would never be rotated.
+//
+// interval is the interval to rotate the file, such as time.Hour, 24*time.Hour.
+// The interval less than one day should divide a day, such as 15m, 1h, 6h.
+// And the interval not less than one day is truncated to the whole days,
+// which are aligned by the Unix epoch in Location, such as 1970-01-01,
+// so that the 7-day interval always starts on Thursday.
+//
+// Default:
+//
+//	fileperm: 0644
+//	interval: 24 * time.Hour
+func NewTimedRotatingFile(pattern string, interval time.Duration,
+	fileperm ...os.FileMode) *TimedRotatingFile {
+	if !hasTimeSpec(pattern) {
+		panic(fmt.Errorf("NewTimedRotatingFile: the pattern '%s' has no time conversion specification", pattern))
+	}
+
+	var filemode os.FileMode = 0644
+	if len(fileperm) > 0 && fileperm[0] > 0 {
+		filemode = fileperm[0]
+	}
+
+	if interval <= 0 {
+		interval = 24 * time.Hour
+	}
+
+	return &TimedRotatingFile{
+		pattern:  pattern,
+		interval: interval,
+		filemode: filemode,
+	}
+}
+
+// TimedRotatingFile is a file rotating logging writer based on the time,
+// which rotates the file at the wall-clock boundary of the interval.
+type TimedRotatingFile struct {
+	// Location is the time zone to calculate the boundary of the interval
+	// and format the filename.
+	//
+	// Default: time.Local
+	Location *time.Location
+
+	// MaxSize is the maximum size of the file. If greater than 0, rotate
+	// the file also when its size reaches MaxSize in the same interval,
+	// and the new file is named with the suffix ".N", such as "app.log.1".
+	//
+	// Default: 0
+	MaxSize int64
+
+	// LinkName is the name of the symlink to the current file if not empty,
+	// which is updated in best effort after rotating.
+	//
+	// Default: ""
+	LinkName string
+
+	// OnRotate is called after rotating the file if set, where oldName is
+	// the name of the last file and newName is the name of the new file.
+	//
+	// Default: nil
+	OnRotate func(oldName, newName string)
+
+	// LockFile is the sidecar lock file, such as "app.lock", which is used
+	// to coordinate the rotation by the size by flock among multiple processes
+	// sharing the same files if not empty. So only one process opens
+	// the next file, and the others switch to it when their file is full.
+	//
+	// Notice: it is only supported on the unix-like platforms,
+	// such as Linux, macOS and BSD.
+	//
+	// Default: ""
+	LockFile string
+
+	// Now is used to get the current time, which is used for test.
+	//
+	// Default: time.Now
+	Now func() time.Time
+
+	// Retention is the retention policy of the backups, that's,
+	// the files matching the filename pattern except the current.
+	Retention
+
+	lock     *fileLock
+	file     *os.File
+	filemode os.FileMode
+	filename string
+	basename string
+	index    int
+	pattern  string
+	interval time.Duration
+	rotateAt time.Time
+	nbytes   int64
+	reopen   int32
+	closed   int32
+}
+
+// Filename returns the name of the current file.
+func (f *TimedRotatingFile) Filename() string { return f.filename }
+
+// Close implements io.Closer.
+func (f *TimedRotatingFile) Close() (err error) {
+	if atomic.CompareAndSwapInt32(&f.closed, 0, 1) {
+		err = f.close()
+		if f.lock != nil {
+			f.lock.Close()
+		}
+	}
+	return
+}
+
+// Reopen reopens the file when writing the data next time, which is used
+// after the file has been rotated by others.
+//
+// It is safe to be called concurrently with Write.
+func (f *TimedRotatingFile) Reopen() error {
+	atomic.StoreInt32(&f.reopen, 1)
+	return nil
+}
+
+// Flush flushes the data to the underlying disk.
+func (f *TimedRotatingFile) Flush() (err error) {
+	if f.file != nil {
+		err = f.file.Sync()
+	}
+	return
+}
+
+// Write implements io.Writer.
+func (f *TimedRotatingFile) Write(data []byte) (n int, err error) {
+	if atomic.LoadInt32(&f.closed) == 1 {
+		return 0, errors.New("the file has been closed")
+	}
+
+	if f.LockFile != "" && f.lock == nil {
+		if f.lock, err = newFileLock(f.LockFile); err != nil {
+			return 0, fmt.Errorf("failed to open the lock file '%s': %s", f.LockFile, err)
+		}
+	}
+
+	// The file may have been written by other processes.
+	if f.lock != nil && f.file != nil && f.MaxSize > 0 {
+		if info, err := f.file.Stat(); err == nil {
+			f.nbytes = info.Size()
+		}
+	}
+
+	now := f.now()
+	reopen := atomic.CompareAndSwapInt32(&f.reopen, 1, 0)
+	if oldName := f.filename; f.file == nil || reopen || !now.Before(f.rotateAt) {
+		if err = f.close(); err != nil {
+			return 0, fmt.Errorf("failed to close the rotating file '%s': %s", f.filename, err)
+		}
+		if f.lock != nil {
+			err = f.openShared(now, len(data))
+		} else {
+			err = f.open(now)
+		}
+		if err != nil {
+			return
+		}
+		f.rotated(oldName)
+	} else if f.MaxSize > 0 && f.nbytes > 0 && f.nbytes+int64(len(data)) > f.MaxSize {
+		if err = f.close(); err != nil {
+			return 0, fmt.Errorf("failed to close the rotating file '%s': %s", f.filename, err)
+		}
+		if f.lock != nil {
+			err = f.openShared(now, len(data))
+		} else {
+			err = f.openNext()
+		}
+		if err != nil {
+			return
+		}
+		f.rotated(oldName)
+	}
+
+	if n, err = f.file.Write(data); err != nil {
+		return
+	}
+
+	f.nbytes += int64(n)
+	return
+}
+
+func (f *TimedRotatingFile) now() time.Time {
+	var now time.Time
+	if f.Now == nil {
+		now = time.Now()
+	} else {
+		now = f.Now()
+	}
+
+	if f.Location == nil {
+		return now.In(time.Local)
+	}
+	return now.In(f.Location)
+}
+
+// open opens the file of the interval containing now, which is the last
+// file of the interval if MaxSize is set.
+func (f *TimedRotatingFile) open(now time.Time) (err error) {
+	start, end := intervalBounds(now, f.interval)
+	f.basename, f.index, f.rotateAt = Strftime(f.pattern, start), 0, end
+	if f.MaxSize > 0 {
+		for fileIsExist(f.indexFilename(f.index + 1)) {
+			f.index++
+		}
+	}
+
+	if err = f.openFile(f.indexFilename(f.index)); err != nil {
+		return
+	}
+
+	if f.MaxSize > 0 && f.nbytes >= f.MaxSize {
+		if err = f.close(); err == nil {
+			err = f.openNext()
+		}
+	} else {
+		f.clean()
+	}
+	return
+}
+
+// openNext opens the next file with the suffix ".N" in the same interval.
+func (f *TimedRotatingFile) openNext() (err error) {
+	f.index++
+	if err = f.openFile(f.indexFilename(f.index)); err == nil {
+		f.clean()
+	}
+	return
+}
+
+// openShared opens the last file of the interval containing now exclusively
+// among the processes, then opens the next file if it has no space for n bytes.
+func (f *TimedRotatingFile) openShared(now time.Time, n int) (err error) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	err = f.open(now)
+	if err == nil && f.MaxSize > 0 && f.nbytes > 0 && f.nbytes+int64(n) > f.MaxSize {
+		if err = f.close(); err == nil {
+			err = f.openNext()
+		}
+	}
+	return
+}
+
+func (f *TimedRotatingFile) rotated(oldName string) {
+	if f.OnRotate != nil && oldName != "" && oldName != f.filename {
+		f.OnRotate(oldName, f.filename)
+	}
+}
+
+func (f *TimedRotatingFile) clean() {
+	if f.Retention.enabled() {
+		backups := timedBackups(f.pattern, f.filename)
+		if f.LockFile != "" {
+			lockfile := filepath.Clean(f.LockFile)
+			for i, backup := range backups {
+				if filepath.Clean(backup) == lockfile {
+					backups = append(backups[:i], backups[i+1:]...)
+					break
+				}
+			}
+		}
+		f.Retention.clean(backups, f.now())
+	}
+}
+
+func (f *TimedRotatingFile) indexFilename(index int) string {
+	if index == 0 {
+		return f.basename
+	}
+	return f.basename + "." + strconv.Itoa(index)
+}
+
+func (f *TimedRotatingFile) openFile(filename string) (err error) {
+	if dir := filepath.Dir(filename); dir != "" && dir != "." {
+		if err = os.MkdirAll(dir, 0755); err != nil {
+			return fmt.Errorf("failed to create the log directory '%s': %s", dir, err)
+		}
+	}
+
+	file, err := os.OpenFile(filename, os.O_CREATE|os.O_APPEND|os.O_WRONLY, f.filemode)
+	if err != nil {
+		return
+	}
+
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return
+	}
+
+	f.file = file
+	f.filename = filename
+	f.nbytes = info.Size()
+	f.symlink()
+	return
+}
+
+func (f *TimedRotatingFile) symlink() {
+	if f.LinkName == "" {
+		return
+	}
+
+	target := f.filename
+	if abs, err := filepath.Abs(target); err == nil {
+		target = abs
+		if absLink, err := filepath.Abs(f.LinkName); err == nil {
+			if rel, err := filepath.Rel(filepath.Dir(absLink), abs); err == nil {
+				target = rel
+			}
+		}
+	}
+
+	// Create the new symlink and rename it to replace the old atomically.
+	tmpname := f.LinkName + ".tmp"
+	os.Remove(tmpname)
+	if err := os.Symlink(target, tmpname); err == nil {
+		if err = os.Rename(tmpname, f.LinkName); err != nil {
+			os.Remove(tmpname)
+		}
+	}
+}
+
+func (f *TimedRotatingFile) close() (err error) {
+	if f.file != nil {
+		err = f.file.Close()
+		f.file = nil
+	}
+	return
+}
+
+// intervalBounds returns the start and end time of the interval
+// containing t, which are aligned by the wall clock in the location of t.
+//
+// The interval not less than one day is truncated to the whole days, which
+// are counted from the Unix epoch in the location, that's, 1970-01-01 00:00,
+// so the boundaries do not depend on when the process starts. The interval
+// less than one day is counted from the midnight of the day, and the last
+// one of the day is truncated by the next midnight.
+//
+// Since the boundaries are the wall-clock time, such as 06:00 and 12:00
+// for the interval 6h, the interval containing the daylight saving time
+// transition is shorter or longer than others by the skipped or repeated
+// time, for example, [00:00, 06:00) is 5h or 7h on that day.
+func intervalBounds(t time.Time, interval time.Duration) (start, end time.Time) {
+	const day = 24 * time.Hour
+	year, month, mday := t.Date()
+	loc := t.Location()
+
+	if interval >= day {
+		days := int64(interval / day)
+		epochDays := time.Date(year, month, mday, 0, 0, 0, 0, time.UTC).Unix() / 86400
+		if mod := epochDays % days; mod < 0 {
+			epochDays -= mod + days
+		} else {
+			epochDays -= mod
+		}
+
+		start = time.Date(1970, 1, 1+int(epochDays), 0, 0, 0, 0, loc)
+		end = time.Date(1970, 1, 1+int(epochDays+days), 0, 0, 0, 0, loc)
+		return
+	}
+
+	hour, minute, second := t.Clock()
+	elapsed := time.Duration(hour)*time.Hour + time.Duration(minute)*time.Minute +
+		time.Duration(second)*time.Second + time.Duration(t.Nanosecond())
+	next := elapsed/interval*interval + interval
+	if next > day {
+		next = day
+	}
+
+	start = wallClock(year, month, mday, elapsed/interval*interval, loc)
+	end = wallClock(year, month, mday, next, loc)
+	if !end.After(t) { // The end is in the repeated time and resolved before t.
+		end = t.Add(next - elapsed)
+	}
+	return
+}
+
+// wallClock returns the time of the wall clock elapsed since the midnight.
+func wallClock(year int, month time.Month, day int, elapsed time.Duration,
+	loc *time.Location) time.Time {
+	sec, nsec := int(elapsed/time.Second), int(elapsed%time.Second)
+	return time.Date(year, month, day, 0, 0, sec, nsec, loc)
+}
+
+/// ----------------------------------------------------------------------- ///
+
+// Strftime formats the time t by the strftime-style layout, which supports
+// the conversion specifications as follow:
+//
+//	%Y: the year with century, such as 2026
+//	%y: the year without century, such as 26
+//	%m: the month, from 01 to 12
+//	%b: the abbreviated month name, such as Jan
+//	%B: the full month name, such as January
+//	%d: the day of the month, from 01 to 31
+//	%j: the day of the year, from 001 to 366
+//	%a: the abbreviated weekday name, such as Mon
+//	%A: the full weekday name, such as Monday
+//	%H: the hour, from 00 to 23
+//	%I: the hour, from 01 to 12
+//	%p: AM or PM
+//	%M: the minute, from 00 to 59
+//	%S: the second, from 00 to 59
+//	%s: the seconds since the Unix epoch
+//	%z: the time zone offset, such as +0800
+//	%Z: the time zone abbreviation, such as UTC
+//	%%: the character '%'
+//
+// The unknown conversion specification is kept as it is.
+func Strftime(layout string, t time.Time) string {
+	buf := make([]byte, 0, len(layout)+16)
+	for i, _len := 0, len(layout); i < _len; i++ {
+		if c := layout[i]; c != '%' || i+1 == _len {
+			buf = append(buf, c)
+			continue
+		}
+
+		i++
+		switch c := layout[i]; c {
+		case 'Y':
+			buf = appendInt(buf, t.Year(), 4)
+		case 'y':
+			buf = appendInt(buf, t.Year()%100, 2)
+		case 'm':
+			buf = appendInt(buf, int(t.Month()), 2)
+		case 'b':
+			buf = append(buf, t.Month().String()[:3]...)
+		case 'B':
+			buf = append(buf, t.Month().String()...)
+		case 'd':
+			buf = appendInt(buf, t.Day(), 2)
+		case 'j':
+			buf = appendInt(buf, t.YearDay(), 3)
+		case 'a':
+			buf = append(buf, t.Weekday().String()[:3]...)
+		case 'A':
+			buf = append(buf, t.Weekday().String()...)
+		case 'H':
+			buf = appendInt(buf, t.Hour(), 2)
+		case 'I':
+			hour := t.Hour() % 12
+			if hour == 0 {
+				hour = 12
+			}
+			buf = appendInt(buf, hour, 2)
+		case 'p':
+			if t.Hour() < 12 {
+				buf = append(buf, "AM"...)
+			} else {
+				buf = append(buf, "PM"...)
+			}
+		case 'M':
+			buf = appendInt(buf, t.Minute(), 2)
+		case 'S':
+			buf = appendInt(buf, t.Second(), 2)
+		case 's':
+			buf = strconv.AppendInt(buf, t.Unix(), 10)
+		case 'z':
+			buf = t.AppendFormat(buf, "-0700")
+		case 'Z':
+			buf = t.AppendFormat(buf, "MST")
+		case '%':
+			buf = append(buf, '%')
+		default:
+			buf = append(buf, '%', c)
+		}
+	}
+	return string(buf)
+}
+
+// appendInt appends the non-negative integer padded with zeros to width.
+func appendInt(buf []byte, v, width int) []byte {
+	for n, x := 1, v; n < width; n++ {
+		if x /= 10; x == 0 {
+			buf = append(buf, '0')
+		}
+	}
+	return strconv.AppendInt(buf, int64(v), 10)
+}
+
+// hasTimeSpec reports whether the layout contains a conversion specification
+// of the time, except for the time zone.
+func hasTimeSpec(layout string) bool {
+	for i, _len := 0, len(layout)-1; i < _len; i++ {
+		if layout[i] == '%' {
+			i++
+			if strings.IndexByte("YymbBdjaAHIpMSs", layout[i]) > -1 {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,193 @@
+// Copyright 2026 xgfone
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package writer
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"runtime"
+	"testing"
+	"time"
+)
+
+func TestStrftime(t *testing.T) {
+	tm := time.Date(2026, time.March, 5, 14, 7, 9, 0, time.UTC)
+	expect := "2026-03-05 14:07:09 26 Mar March 064 Thu Thursday 02PM 1772719629 +0000 UTC % %Q % %"
+	result := Strftime("%Y-%m-%d %H:%M:%S %y %b %B %j %a %A %I%p %s %z %Z %% %Q % %", tm)
+	if result != expect {
+		t.Errorf("expect '%s', but got '%s'", expect, result)
+	}
+}
+
+func TestTimedRotatingFile(t *testing.T) {
+	dir, err := ioutil.TempDir("", "timed_rotating_file")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	loc := time.FixedZone("UTC+8", 8*3600)
+	now := time.Date(2026, time.October, 16, 23, 59, 0, 0, loc)
+
+	file := NewTimedRotatingFile(filepath.Join(dir, "%Y", "app.%Y-%m-%d.log"), 0)
+	file.Now = func() time.Time { return now.UTC() }
+	file.Location = loc
+	file.MaxSize = 10
+	file.LinkName = filepath.Join(dir, "current.log")
+	defer file.Close()
+
+	var rotations []string
+	file.OnRotate = func(oldName, newName string) {
+		oldName, _ = filepath.Rel(dir, oldName)
+		newName, _ = filepath.Rel(dir, newName)
+		rotations = append(rotations, filepath.ToSlash(oldName)+" -> "+filepath.ToSlash(newName))
+	}
+
+	write := func(data string) {
+		if _, err := file.Write([]byte(data)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	write("01234")
+	write("56789") // Full
+	write("abc")   // Rotate by the size
+	now = now.Add(time.Minute)
+	write("def") // Rotate by the time
+	write("ghi")
+
+	expects := map[string]string{
+		"2026/app.2026-10-16.log":   "0123456789",
+		"2026/app.2026-10-16.log.1": "abc",
+		"2026/app.2026-10-17.log":   "defghi",
+	}
+	expectRotations := []string{
+		"2026/app.2026-10-16.log -> 2026/app.2026-10-16.log.1",
+		"2026/app.2026-10-16.log.1 -> 2026/app.2026-10-17.log",
+	}
+	if !reflect.DeepEqual(rotations, expectRotations) {
+		t.Errorf("expect rotations %v, but got %v", expectRotations, rotations)
+	}
+
+	for name, expect := range expects {
+		data, err := ioutil.ReadFile(filepath.Join(dir, name))
+		if err != nil {
+			t.Error(err)
+		} else if string(data) != expect {
+			t.Errorf("%s: expect '%s', but got '%s'", name, expect, data)
+		}
+	}
+
+	if runtime.GOOS != "windows" {
+		if target, err := os.Readlink(file.LinkName); err != nil {
+			t.Error(err)
+		} else if expect := filepath.Join("2026", "app.2026-10-17.log"); target != expect {
+			t.Errorf("expect the link target '%s', but got '%s'", expect, target)
+		}
+	}
+
+	// Reopen the file in the same interval.
+	file.Close()
+	file = NewTimedRotatingFile(filepath.Join(dir, "%Y", "app.%Y-%m-%d.log"), time.Hour)
+	file.Now = func() time.Time { return now }
+	file.MaxSize = 10
+	now = now.Add(-time.Minute)
+	write("jkl")
+	if expect := filepath.Join(dir, "2026", "app.2026-10-16.log.1"); file.Filename() != expect {
+		t.Errorf("expect the file '%s', but got '%s'", expect, file.Filename())
+	}
+}
+
+func TestIntervalBounds(t *testing.T) {
+	tm := time.Date(2026, time.October, 16, 13, 45, 30, 0, time.UTC)
+	tests := []struct {
+		Interval   time.Duration
+		Start, End time.Time
+	}{
+		{15 * time.Minute, tm.Add(-30*time.Second - 0*time.Minute), tm.Add(14*time.Minute + 30*time.Second)},
+		{time.Hour, tm.Add(-45*time.Minute - 30*time.Second), tm.Add(14*time.Minute + 30*time.Second)},
+		{7 * time.Hour, tm.Add(-6*time.Hour - 45*time.Minute - 30*time.Second), tm.Add(14*time.Minute + 30*time.Second)},
+		{48 * time.Hour, time.Date(2026, time.October, 16, 0, 0, 0, 0, time.UTC), time.Date(2026, time.October, 18, 0, 0, 0, 0, time.UTC)},
+		{7 * 24 * time.Hour, time.Date(2026, time.October, 15, 0, 0, 0, 0, time.UTC), time.Date(2026, time.October, 22, 0, 0, 0, 0, time.UTC)},
+	}
+
+	for _, test := range tests {
+		start, end := intervalBounds(tm, test.Interval)
+		if !start.Equal(test.Start) || !end.Equal(test.End) {
+			t.Errorf("%s: expect [%s, %s), but got [%s, %s)", test.Interval, test.Start, test.End, start, end)
+		}
+	}
+
+	// The last interval is truncated by the next day.
+	start, end := intervalBounds(tm.Add(9*time.Hour), 7*time.Hour)
+	if expect := time.Date(2026, time.October, 16, 21, 0, 0, 0, time.UTC); !start.Equal(expect) {
+		t.Errorf("expect start '%s', but got '%s'", expect, start)
+	}
+	if expect := time.Date(2026, time.October, 17, 0, 0, 0, 0, time.UTC); !end.Equal(expect) {
+		t.Errorf("expect end '%s', but got '%s'", expect, end)
+	}
+}
+
+func TestIntervalBoundsDST(t *testing.T) {
+	loc, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Skip(err)
+	}
+
+	tests := []struct {
+		Now        time.Time
+		Interval   time.Duration
+		Start, End string
+	}{
+		// 2026-03-08 02:00 EST is skipped to 03:00 EDT.
+		{time.Date(2026, time.March, 8, 1, 30, 0, 0, loc), 6 * time.Hour, "2026-03-08T00:00:00-05:00", "2026-03-08T06:00:00-04:00"},
+		{time.Date(2026, time.March, 8, 7, 0, 0, 0, loc), 6 * time.Hour, "2026-03-08T06:00:00-04:00", "2026-03-08T12:00:00-04:00"},
+		{time.Date(2026, time.March, 8, 1, 30, 0, 0, loc), time.Hour, "2026-03-08T01:00:00-05:00", "2026-03-08T03:00:00-04:00"},
+		{time.Date(2026, time.March, 8, 12, 0, 0, 0, loc), 7 * 24 * time.Hour, "2026-03-05T00:00:00-05:00", "2026-03-12T00:00:00-04:00"},
+
+		// 2026-11-01 02:00 EDT is repeated as 01:00 EST.
+		{time.Date(2026, time.November, 1, 7, 0, 0, 0, loc), 6 * time.Hour, "2026-11-01T06:00:00-05:00", "2026-11-01T12:00:00-05:00"},
+		{time.Date(2026, time.November, 1, 6, 15, 0, 0, time.UTC).In(loc), 30 * time.Minute, "2026-11-01T01:00:00-04:00", "2026-11-01T01:30:00-05:00"},
+	}
+
+	for _, test := range tests {
+		start, end := intervalBounds(test.Now, test.Interval)
+		if s, e := start.Format(time.RFC3339), end.Format(time.RFC3339); s != test.Start || e != test.End {
+			t.Errorf("%s %s: expect [%s, %s), but got [%s, %s)", test.Now, test.Interval, test.Start, test.End, s, e)
+		}
+	}
+}
+
+func TestNewTimedRotatingFilePattern(t *testing.T) {
+	for pattern, ok := range map[string]bool{
+		"app.%Y%m%d.log": true,
+		"app.%H.log":     true,
+		"app.log":        false,
+		"app.%%Y.log":    false,
+		"app.%z.log":     false,
+		"app.log%":       false,
+	} {
+		func() {
+			defer func() {
+				if err := recover(); (err == nil) != ok {
+					t.Errorf("%s: unexpected panic '%v'", pattern, err)
+				}
+			}()
+			NewTimedRotatingFile(pattern, 0)
+		}()
+	}
+}
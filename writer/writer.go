@@ -19,6 +19,15 @@ import (
 	"bufio"
 	"io"
 	"sync"
+
+	"github.com/xgfone/go-log/internal/term"
+)
+
+// The levels used by the writers, which mirror log.LvlError and log.LvlPanic,
+// because the package log cannot be imported to avoid the import cycle.
+const (
+	LevelError = 80
+	LevelPanic = 120
 )
 
 // Discard is the writer to discard all the written data.
@@ -59,6 +68,26 @@ type Flusher interface {
 	Flush() error
 }
 
+// Reopen reopens the writer if it has implemented the interface Reopener.
+func Reopen(writer io.Writer) (err error) {
+	switch w := writer.(type) {
+	case Reopener:
+		return w.Reopen()
+
+	case WrappedWriter:
+		return Reopen(w.UnwrapWriter())
+
+	default:
+		return nil
+	}
+}
+
+// Reopener is used to reopen the underlying file of the writer,
+// for example, after it has been rotated by the external tool like logrotate.
+type Reopener interface {
+	Reopen() error
+}
+
 /// ----------------------------------------------------------------------- ///
 
 // WrappedWriter is a writer which wraps and returns the inner writer.
@@ -81,6 +110,13 @@ func UnwrapWriter(writer io.Writer) io.Writer {
 	return writer
 }
 
+// IsTerminal reports whether the innermost writer unwrapped by UnwrapWriter
+// is an *os.File referring to a terminal, which is detected by ioctl
+// on the unix-like platforms and by the console mode on windows.
+func IsTerminal(writer io.Writer) bool {
+	return term.IsTerminal(writer)
+}
+
 /// ----------------------------------------------------------------------- ///
 
 type safeWriter struct {
@@ -146,6 +182,9 @@ func (w bufWriter) Close() error                { w.Flush(); return Close(w.w) }
 // all the datas into the wrapped writer when the buffer is full.
 //
 // If bufSize is equal to or less than 0, it is 4096 by default.
+//
+// Notice: it is not thread-safe and only flushed when the buffer is full,
+// so use BufferedWriter instead to flush the buffer periodically.
 func BufferWriter(writer io.Writer, bufSize int) io.WriteCloser {
 	if writer == nil {
 		panic("BufferWriter: the wrapped writer is nil")
@@ -17,6 +17,9 @@ package writer
 import (
 	"bytes"
 	"io"
+	"io/ioutil"
+	"os"
+	"runtime"
 	"strings"
 	"testing"
 )
@@ -65,3 +68,33 @@ func TestFlush(t *testing.T) {
 		t.Errorf("expect '%s', but got '%s'", expect, s)
 	}
 }
+
+func TestIsTerminal(t *testing.T) {
+	if IsTerminal(bytes.NewBuffer(nil)) {
+		t.Error("expect the buffer is not a terminal")
+	}
+
+	file, err := ioutil.TempFile("", "terminal")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.Remove(file.Name())
+	defer file.Close()
+
+	if IsTerminal(SafeWriter(lwriter{file})) {
+		t.Error("expect the regular file is not a terminal")
+	}
+
+	switch runtime.GOOS {
+	case "linux", "darwin", "dragonfly", "freebsd", "netbsd", "openbsd", "windows":
+		null, err := os.OpenFile(os.DevNull, os.O_WRONLY, 0)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer null.Close()
+
+		if IsTerminal(null) {
+			t.Errorf("expect %s is not a terminal", os.DevNull)
+		}
+	}
+}